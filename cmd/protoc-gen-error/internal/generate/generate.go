@@ -3,6 +3,7 @@ package generate
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/codermuhao/tools/cmd/protoc-gen-error/internal/util"
 
@@ -10,6 +11,7 @@ import (
 
 	"google.golang.org/protobuf/compiler/protogen"
 	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
 	"google.golang.org/protobuf/types/pluginpb"
 )
 
@@ -28,6 +30,8 @@ var SupportedFeatures = uint64(pluginpb.CodeGeneratorResponse_FEATURE_PROTO3_OPT
 // NewGen create a gen instance
 func NewGen(g *protogen.Plugin) *gen {
 	return &gen{g: g, pkgs: []pkgImport{
+		{url: "context"},
+		{url: "time"},
 		{url: "git.woa.com/enbox/enkits/xerrors"},
 	}}
 }
@@ -45,6 +49,9 @@ func (g *gen) File(file *protogen.File, release string) *protogen.GeneratedFile
 	g.genImports(gf)
 	gf.P()
 	gf.P("var _ = xerrors.NewReasonError")
+	gf.P("var _ context.Context")
+	gf.P("var _ = time.Second")
+	var registers []string
 	for _, v := range file.Proto.GetEnumType() {
 		ext := proto.GetExtension(v.GetOptions(), options.E_Enable)
 		enable, ok := ext.(bool)
@@ -61,39 +68,103 @@ func (g *gen) File(file *protogen.File, release string) *protogen.GeneratedFile
 			if !ok {
 				panic("reason prefix is error")
 			}
-			gf.P("func Is" + util.Case2Camel(vv.GetName()) + "(err error) bool {")
-			gf.P("e := xerrors.Parse(err)")
+			reason, prefixName := fmt.Sprintf("%s_%s.String()", v.GetName(), vv.GetName()), ""
 			if prefix > 0 {
-				gf.P(fmt.Sprintf("return e.Reason == %#v+%s_%s.String()", prefix.String()+".",
-					v.GetName(), vv.GetName()))
-			} else {
-				gf.P(fmt.Sprintf("return e.Reason == %s_%s.String()", v.GetName(), vv.GetName()))
+				reason, prefixName = fmt.Sprintf("%#v+%s", prefix.String()+".", reason), prefix.String()
 			}
+			name := util.Case2Camel(vv.GetName())
+			gf.P("func Is" + name + "(err error) bool {")
+			gf.P("e := xerrors.Parse(err)")
+			gf.P(fmt.Sprintf("return e.Reason == %s", reason))
 			gf.P("}")
-			gf.P()
-			gf.P(fmt.Sprintf("func New%s(format string, args ...interface{}) *xerrors.ReasonError {",
-				util.Case2Camel(vv.GetName())))
 			ext2 := proto.GetExtension(vv.GetOptions(), options.E_Message)
-			message, ok := ext2.(string)
-			if ok && len(message) > 0 {
-				gf.P("if len(format) == 0 {")
-				gf.P("format,args = \"" + message + "\", args[:0]")
-				gf.P("}")
-			}
-			if prefix > 0 {
-				gf.P(fmt.Sprintf("return xerrors.NewReasonErrorf(%#v+%s_%s.String(), format, args...)",
-					prefix.String()+".", v.GetName(), vv.GetName()))
-			} else {
-				gf.P(fmt.Sprintf("return xerrors.NewReasonErrorf(%s_%s.String(), format, args...)",
-					v.GetName(), vv.GetName()))
-			}
+			message, _ := ext2.(string)
+			retry := genRetry(vv)
+			gf.P()
+			gf.P(fmt.Sprintf("func New%s(format string, args ...interface{}) *xerrors.ReasonError {", name))
+			genDefaultMessage(gf, message)
+			gf.P(fmt.Sprintf("return xerrors.NewReasonErrorf(%s, format, args...)%s", reason, retry))
 			gf.P("}")
+			gf.P()
+			gf.P(fmt.Sprintf("func New%sCtx(ctx context.Context, format string, args ...interface{}) *xerrors.ReasonError {",
+				name))
+			genDefaultMessage(gf, message)
+			gf.P(fmt.Sprintf("return xerrors.NewReasonErrorfCtx(ctx, %s, format, args...)%s", reason, retry))
+			gf.P("}")
+			registers = append(registers, fmt.Sprintf(
+				"xerrors.MustRegisterReason(xerrors.ReasonInfo{Reason: %s, Message: %#v, Prefix: %#v, Source: %#v})",
+				reason, message, prefixName, file.Proto.GetName()))
+		}
+	}
+	if len(registers) > 0 {
+		gf.P()
+		gf.P("func init() {")
+		for _, v := range registers {
+			gf.P(v)
 		}
+		gf.P("}")
 	}
 	gf.P()
 	return gf
 }
 
+// genDefaultMessage format为空时使用message选项作为默认消息
+func genDefaultMessage(gf *protogen.GeneratedFile, message string) {
+	if len(message) > 0 {
+		gf.P("if len(format) == 0 {")
+		gf.P("format,args = \"" + message + "\", args[:0]")
+		gf.P("}")
+	}
+}
+
+// genRetry 根据retry和retry_after选项生成设置重试分类的调用链，只设置retry_after时分类为RateLimited
+func genRetry(vv *descriptorpb.EnumValueDescriptorProto) string {
+	ext := proto.GetExtension(vv.GetOptions(), options.E_Retry)
+	retry, ok := ext.(options.RetryClass)
+	if !ok {
+		panic("retry class is error")
+	}
+	ext1 := proto.GetExtension(vv.GetOptions(), options.E_RetryAfter)
+	after, ok := ext1.(string)
+	if !ok {
+		panic("retry_after must a string value")
+	}
+	if len(after) > 0 {
+		d, err := time.ParseDuration(after)
+		if err != nil {
+			panic(fmt.Sprintf("retry_after of %s is invalid: %v", vv.GetName(), err))
+		}
+		if retry == options.RetryClass_RetryUnspecified {
+			retry = options.RetryClass_RateLimited
+		}
+		return fmt.Sprintf(".WithRetryPolicy(xerrors.%s, %s)", retry.String(), durationExpr(d))
+	}
+	if retry > 0 {
+		return fmt.Sprintf(".WithRetry(xerrors.%s)", retry.String())
+	}
+	return ""
+}
+
+// durationExpr 用能整除d的最大单位输出time.Duration表达式，例如30*time.Second
+func durationExpr(d time.Duration) string {
+	units := []struct {
+		d    time.Duration
+		name string
+	}{
+		{time.Hour, "time.Hour"},
+		{time.Minute, "time.Minute"},
+		{time.Second, "time.Second"},
+		{time.Millisecond, "time.Millisecond"},
+		{time.Microsecond, "time.Microsecond"},
+	}
+	for _, u := range units {
+		if d%u.d == 0 {
+			return fmt.Sprintf("%d*%s", d/u.d, u.name)
+		}
+	}
+	return fmt.Sprintf("time.Duration(%d)", d)
+}
+
 func (g *gen) genImports(gf *protogen.GeneratedFile) {
 	gf.P("import (")
 	for _, v := range g.pkgs {
@@ -8,9 +8,9 @@ package reason
 
 import (
 	proto "github.com/golang/protobuf/proto"
-	descriptor "github.com/golang/protobuf/protoc-gen-go/descriptor"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	descriptorpb "google.golang.org/protobuf/types/descriptorpb"
 	reflect "reflect"
 	sync "sync"
 )
@@ -93,9 +93,65 @@ func (PrefixErrorReason) EnumDescriptor() ([]byte, []int) {
 	return file_reason_reason_proto_rawDescGZIP(), []int{0}
 }
 
+// RetryClass 错误的重试分类，与xerrors.RetryClass取值一致
+type RetryClass int32
+
+const (
+	RetryClass_RetryUnspecified RetryClass = 0
+	RetryClass_Retryable        RetryClass = 1
+	RetryClass_Temporary        RetryClass = 2
+	RetryClass_Permanent        RetryClass = 3
+	RetryClass_RateLimited      RetryClass = 4
+)
+
+// Enum value maps for RetryClass.
+var (
+	RetryClass_name = map[int32]string{
+		0: "RetryUnspecified",
+		1: "Retryable",
+		2: "Temporary",
+		3: "Permanent",
+		4: "RateLimited",
+	}
+	RetryClass_value = map[string]int32{
+		"RetryUnspecified": 0,
+		"Retryable":        1,
+		"Temporary":        2,
+		"Permanent":        3,
+		"RateLimited":      4,
+	}
+)
+
+func (x RetryClass) Enum() *RetryClass {
+	p := new(RetryClass)
+	*p = x
+	return p
+}
+
+func (x RetryClass) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (RetryClass) Descriptor() protoreflect.EnumDescriptor {
+	return file_reason_reason_proto_enumTypes[1].Descriptor()
+}
+
+func (RetryClass) Type() protoreflect.EnumType {
+	return &file_reason_reason_proto_enumTypes[1]
+}
+
+func (x RetryClass) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use RetryClass.Descriptor instead.
+func (RetryClass) EnumDescriptor() ([]byte, []int) {
+	return file_reason_reason_proto_rawDescGZIP(), []int{1}
+}
+
 var file_reason_reason_proto_extTypes = []protoimpl.ExtensionInfo{
 	{
-		ExtendedType:  (*descriptor.EnumOptions)(nil),
+		ExtendedType:  (*descriptorpb.EnumOptions)(nil),
 		ExtensionType: (*bool)(nil),
 		Field:         1108,
 		Name:          "reason.enable",
@@ -103,7 +159,7 @@ var file_reason_reason_proto_extTypes = []protoimpl.ExtensionInfo{
 		Filename:      "reason/reason.proto",
 	},
 	{
-		ExtendedType:  (*descriptor.EnumValueOptions)(nil),
+		ExtendedType:  (*descriptorpb.EnumValueOptions)(nil),
 		ExtensionType: (*string)(nil),
 		Field:         1109,
 		Name:          "reason.message",
@@ -111,27 +167,49 @@ var file_reason_reason_proto_extTypes = []protoimpl.ExtensionInfo{
 		Filename:      "reason/reason.proto",
 	},
 	{
-		ExtendedType:  (*descriptor.EnumValueOptions)(nil),
+		ExtendedType:  (*descriptorpb.EnumValueOptions)(nil),
 		ExtensionType: (*PrefixErrorReason)(nil),
 		Field:         1110,
 		Name:          "reason.prefix",
 		Tag:           "varint,1110,opt,name=prefix,enum=reason.PrefixErrorReason",
 		Filename:      "reason/reason.proto",
 	},
+	{
+		ExtendedType:  (*descriptorpb.EnumValueOptions)(nil),
+		ExtensionType: (*RetryClass)(nil),
+		Field:         1111,
+		Name:          "reason.retry",
+		Tag:           "varint,1111,opt,name=retry,enum=reason.RetryClass",
+		Filename:      "reason/reason.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.EnumValueOptions)(nil),
+		ExtensionType: (*string)(nil),
+		Field:         1112,
+		Name:          "reason.retry_after",
+		Tag:           "bytes,1112,opt,name=retry_after",
+		Filename:      "reason/reason.proto",
+	},
 }
 
-// Extension fields to descriptor.EnumOptions.
+// Extension fields to descriptorpb.EnumOptions.
 var (
 	// optional bool enable = 1108;
 	E_Enable = &file_reason_reason_proto_extTypes[0]
 )
 
-// Extension fields to descriptor.EnumValueOptions.
+// Extension fields to descriptorpb.EnumValueOptions.
 var (
 	// optional string message = 1109;
 	E_Message = &file_reason_reason_proto_extTypes[1]
 	// optional reason.PrefixErrorReason prefix = 1110;
 	E_Prefix = &file_reason_reason_proto_extTypes[2]
+	// optional reason.RetryClass retry = 1111;
+	E_Retry = &file_reason_reason_proto_extTypes[3]
+	// 重试间隔，time.ParseDuration格式，例如"1s"，未设置retry时错误归类为RateLimited
+	//
+	// optional string retry_after = 1112;
+	E_RetryAfter = &file_reason_reason_proto_extTypes[4]
 )
 
 var File_reason_reason_proto protoreflect.FileDescriptor
@@ -153,24 +231,40 @@ var file_reason_reason_proto_rawDesc = []byte{
 	0x6f, 0x72, 0x69, 0x7a, 0x65, 0x64, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x10,
 	0x06, 0x12, 0x14, 0x0a, 0x10, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x4e, 0x6f, 0x74,
 	0x46, 0x6f, 0x75, 0x6e, 0x64, 0x10, 0x07, 0x12, 0x13, 0x0a, 0x0f, 0x46, 0x61, 0x69, 0x6c, 0x65,
-	0x64, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x10, 0x08, 0x3a, 0x35, 0x0a, 0x06,
-	0x65, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x12, 0x1c, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6e, 0x75, 0x6d, 0x4f, 0x70, 0x74,
-	0x69, 0x6f, 0x6e, 0x73, 0x18, 0xd4, 0x08, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x65, 0x6e, 0x61,
-	0x62, 0x6c, 0x65, 0x3a, 0x3c, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x21,
-	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
-	0x2e, 0x45, 0x6e, 0x75, 0x6d, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e,
-	0x73, 0x18, 0xd5, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67,
-	0x65, 0x3a, 0x55, 0x0a, 0x06, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x12, 0x21, 0x2e, 0x67, 0x6f,
-	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6e,
-	0x75, 0x6d, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0xd6,
-	0x08, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x19, 0x2e, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x2e, 0x50,
-	0x72, 0x65, 0x66, 0x69, 0x78, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e,
-	0x52, 0x06, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x42, 0x3d, 0x5a, 0x3b, 0x67, 0x69, 0x74, 0x2e,
-	0x77, 0x6f, 0x61, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x65, 0x6e, 0x62, 0x6f, 0x78, 0x2f, 0x65, 0x6e,
-	0x6b, 0x69, 0x74, 0x73, 0x2f, 0x63, 0x6d, 0x64, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x2d,
-	0x67, 0x65, 0x6e, 0x2d, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x2f, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e,
-	0x3b, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x64, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x10, 0x08, 0x2a, 0x60, 0x0a, 0x0a,
+	0x52, 0x65, 0x74, 0x72, 0x79, 0x43, 0x6c, 0x61, 0x73, 0x73, 0x12, 0x14, 0x0a, 0x10, 0x52, 0x65,
+	0x74, 0x72, 0x79, 0x55, 0x6e, 0x73, 0x70, 0x65, 0x63, 0x69, 0x66, 0x69, 0x65, 0x64, 0x10, 0x00,
+	0x12, 0x0d, 0x0a, 0x09, 0x52, 0x65, 0x74, 0x72, 0x79, 0x61, 0x62, 0x6c, 0x65, 0x10, 0x01, 0x12,
+	0x0d, 0x0a, 0x09, 0x54, 0x65, 0x6d, 0x70, 0x6f, 0x72, 0x61, 0x72, 0x79, 0x10, 0x02, 0x12, 0x0d,
+	0x0a, 0x09, 0x50, 0x65, 0x72, 0x6d, 0x61, 0x6e, 0x65, 0x6e, 0x74, 0x10, 0x03, 0x12, 0x0f, 0x0a,
+	0x0b, 0x52, 0x61, 0x74, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x65, 0x64, 0x10, 0x04, 0x3a, 0x35,
+	0x0a, 0x06, 0x65, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x12, 0x1c, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6e, 0x75, 0x6d, 0x4f,
+	0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0xd4, 0x08, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x65,
+	0x6e, 0x61, 0x62, 0x6c, 0x65, 0x3a, 0x3c, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
+	0x12, 0x21, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x45, 0x6e, 0x75, 0x6d, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x4f, 0x70, 0x74, 0x69,
+	0x6f, 0x6e, 0x73, 0x18, 0xd5, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73,
+	0x61, 0x67, 0x65, 0x3a, 0x55, 0x0a, 0x06, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x12, 0x21, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x45, 0x6e, 0x75, 0x6d, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73,
+	0x18, 0xd6, 0x08, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x19, 0x2e, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e,
+	0x2e, 0x50, 0x72, 0x65, 0x66, 0x69, 0x78, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x52, 0x65, 0x61, 0x73,
+	0x6f, 0x6e, 0x52, 0x06, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x3a, 0x4c, 0x0a, 0x05, 0x72, 0x65,
+	0x74, 0x72, 0x79, 0x12, 0x21, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6e, 0x75, 0x6d, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x4f,
+	0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0xd7, 0x08, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x12, 0x2e,
+	0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x2e, 0x52, 0x65, 0x74, 0x72, 0x79, 0x43, 0x6c, 0x61, 0x73,
+	0x73, 0x52, 0x05, 0x72, 0x65, 0x74, 0x72, 0x79, 0x3a, 0x43, 0x0a, 0x0b, 0x72, 0x65, 0x74, 0x72,
+	0x79, 0x5f, 0x61, 0x66, 0x74, 0x65, 0x72, 0x12, 0x21, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6e, 0x75, 0x6d, 0x56, 0x61,
+	0x6c, 0x75, 0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0xd8, 0x08, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0a, 0x72, 0x65, 0x74, 0x72, 0x79, 0x41, 0x66, 0x74, 0x65, 0x72, 0x42, 0x40, 0x5a,
+	0x3e, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x63, 0x6f, 0x64, 0x65,
+	0x72, 0x6d, 0x75, 0x68, 0x61, 0x6f, 0x2f, 0x74, 0x6f, 0x6f, 0x6c, 0x73, 0x2f, 0x63, 0x6d, 0x64,
+	0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x2d, 0x67, 0x65, 0x6e, 0x2d, 0x65, 0x72, 0x72, 0x6f,
+	0x72, 0x2f, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x3b, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x62,
+	0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -185,21 +279,25 @@ func file_reason_reason_proto_rawDescGZIP() []byte {
 	return file_reason_reason_proto_rawDescData
 }
 
-var file_reason_reason_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_reason_reason_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
 var file_reason_reason_proto_goTypes = []interface{}{
-	(PrefixErrorReason)(0),              // 0: reason.PrefixErrorReason
-	(*descriptor.EnumOptions)(nil),      // 1: google.protobuf.EnumOptions
-	(*descriptor.EnumValueOptions)(nil), // 2: google.protobuf.EnumValueOptions
+	(PrefixErrorReason)(0),                // 0: reason.PrefixErrorReason
+	(RetryClass)(0),                       // 1: reason.RetryClass
+	(*descriptorpb.EnumOptions)(nil),      // 2: google.protobuf.EnumOptions
+	(*descriptorpb.EnumValueOptions)(nil), // 3: google.protobuf.EnumValueOptions
 }
 var file_reason_reason_proto_depIdxs = []int32{
-	1, // 0: reason.enable:extendee -> google.protobuf.EnumOptions
-	2, // 1: reason.message:extendee -> google.protobuf.EnumValueOptions
-	2, // 2: reason.prefix:extendee -> google.protobuf.EnumValueOptions
-	0, // 3: reason.prefix:type_name -> reason.PrefixErrorReason
-	4, // [4:4] is the sub-list for method output_type
-	4, // [4:4] is the sub-list for method input_type
-	3, // [3:4] is the sub-list for extension type_name
-	0, // [0:3] is the sub-list for extension extendee
+	2, // 0: reason.enable:extendee -> google.protobuf.EnumOptions
+	3, // 1: reason.message:extendee -> google.protobuf.EnumValueOptions
+	3, // 2: reason.prefix:extendee -> google.protobuf.EnumValueOptions
+	3, // 3: reason.retry:extendee -> google.protobuf.EnumValueOptions
+	3, // 4: reason.retry_after:extendee -> google.protobuf.EnumValueOptions
+	0, // 5: reason.prefix:type_name -> reason.PrefixErrorReason
+	1, // 6: reason.retry:type_name -> reason.RetryClass
+	7, // [7:7] is the sub-list for method output_type
+	7, // [7:7] is the sub-list for method input_type
+	5, // [5:7] is the sub-list for extension type_name
+	0, // [0:5] is the sub-list for extension extendee
 	0, // [0:0] is the sub-list for field type_name
 }
 
@@ -213,9 +311,9 @@ func file_reason_reason_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_reason_reason_proto_rawDesc,
-			NumEnums:      1,
+			NumEnums:      2,
 			NumMessages:   0,
-			NumExtensions: 3,
+			NumExtensions: 5,
 			NumServices:   0,
 		},
 		GoTypes:           file_reason_reason_proto_goTypes,
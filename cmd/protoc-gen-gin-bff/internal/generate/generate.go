@@ -168,11 +168,21 @@ func (g *gen) genServiceConstruct(srv *descriptorpb.ServiceDescriptorProto) {
 		g.pkgs = append(g.pkgs, pkgImport{url: "net/http"})
 		g.pkgExists["net/http"] = struct{}{}
 	}
+	if _, ok := g.pkgExists["git.woa.com/enbox/enkits/xerrors"]; !ok {
+		g.pkgs = append(g.pkgs, pkgImport{url: "git.woa.com/enbox/enkits/xerrors"})
+		g.pkgExists["git.woa.com/enbox/enkits/xerrors"] = struct{}{}
+	}
 	service += fmt.Sprintf("s := &%sBFF {\n", firstLowerName)
 	service += fmt.Sprintf("h: h,\n")
 	service += fmt.Sprintf("errorFunc: func(c *gin.Context, err error) {\n")
 	service += fmt.Sprintf("c.Error(err)\n")
-	service += fmt.Sprintf("c.AbortWithError(http.StatusInternalServerError, err)\n")
+	service += fmt.Sprintf("xerrors.ObserveReturn(err)\n")
+	service += fmt.Sprintf("xerrors.RecordOnSpan(c.Request.Context(), err)\n")
+	service += fmt.Sprintf("code, contentType, body := xerrors.MarshalHTTP(err, c.GetHeader(%#v), "+
+		"xerrors.WithProblemInstance(c.Request.URL.Path), xerrors.WithProblemLanguage(c.GetHeader(%#v)))\n",
+		"Accept", "Accept-Language")
+	service += fmt.Sprintf("c.Abort()\n")
+	service += fmt.Sprintf("c.Data(code, contentType, body)\n")
 	service += fmt.Sprintf("return\n")
 	service += fmt.Sprintf("},\n")
 	service += fmt.Sprintf("rspFunc: func(c *gin.Context, i interface{}) {\n")
@@ -195,6 +205,8 @@ func (g *gen) genErrorAndSuccessFunc(srv *descriptorpb.ServiceDescriptorProto) {
 		srv.GetName(), firstLowerName)
 	service += fmt.Sprintf("return func(s *%sBFF) {\n", firstLowerName)
 	service += fmt.Sprintf("s.errorFunc = func(c *gin.Context, err error) {\n")
+	service += fmt.Sprintf("xerrors.ObserveReturn(err)\n")
+	service += fmt.Sprintf("xerrors.RecordOnSpan(c.Request.Context(), err)\n")
 	service += fmt.Sprintf("f(c, err)\n")
 	service += fmt.Sprintf("}\n")
 	service += fmt.Sprintf("}\n")
@@ -228,20 +240,25 @@ func (g *gen) genMiddlewares(srv *descriptorpb.ServiceDescriptorProto) {
 func (g *gen) genMethod(i *router) {
 	service += fmt.Sprintf("{\n")
 	service += fmt.Sprintf("handlers := append(b.routerMiddlewares[%#v], func(ctx *gin.Context) {\n", i.url)
-	service += fmt.Sprintf("raw, err := ctx.GetRawData()\n")
-	service += fmt.Sprintf("if err != nil {\n")
-	service += fmt.Sprintf("b.errorFunc(ctx, err)\n")
-	service += fmt.Sprintf("return\n")
-	service += fmt.Sprintf("}\n")
-	service += fmt.Sprintf("if len(raw) == 0 {\n")
-	service += fmt.Sprintf("raw = []byte(%#v)\n", "{}")
-	service += fmt.Sprintf("}\n")
+	service += fmt.Sprintf("defer xerrors.RecoverWith(func(se *xerrors.ReasonError) {\n")
+	service += fmt.Sprintf("b.errorFunc(ctx, se)\n")
+	service += fmt.Sprintf("})\n")
 	service += fmt.Sprintf("req := new(%s)\n", g.formatType(i.input))
 	if _, ok := g.pkgExists["git.woa.com/enbox/enkits/xjson"]; !ok {
 		g.pkgs = append(g.pkgs, pkgImport{url: "git.woa.com/enbox/enkits/xjson"})
 		g.pkgExists["git.woa.com/enbox/enkits/xjson"] = struct{}{}
 	}
-	service += fmt.Sprintf("if err := xjson.Unmarshal(raw, req); err != nil {\n")
+	if _, ok := g.pkgExists["io"]; !ok {
+		g.pkgs = append(g.pkgs, pkgImport{url: "io"})
+		g.pkgExists["io"] = struct{}{}
+	}
+	// 空body等同于{}，body中请求之后不能有其他数据
+	service += fmt.Sprintf("dec := xjson.NewDecoder(ctx.Request.Body)\n")
+	service += fmt.Sprintf("if err := dec.Decode(req); err != nil && err != io.EOF {\n")
+	service += fmt.Sprintf("b.errorFunc(ctx, err)\n")
+	service += fmt.Sprintf("return\n")
+	service += fmt.Sprintf("}\n")
+	service += fmt.Sprintf("if err := dec.Finish(); err != nil {\n")
 	service += fmt.Sprintf("b.errorFunc(ctx, err)\n")
 	service += fmt.Sprintf("return\n")
 	service += fmt.Sprintf("}\n")
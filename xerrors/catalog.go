@@ -0,0 +1,73 @@
+package xerrors
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/codermuhao/tools/xjson"
+)
+
+// ReasonInfo reason的登记信息，由protoc-gen-error生成的代码在init中登记
+type ReasonInfo struct {
+	Reason  string `json:"reason"`
+	Message string `json:"message"`
+	Prefix  string `json:"prefix"`
+	Source  string `json:"source"`
+}
+
+var (
+	catalogMu sync.RWMutex
+	catalog   = make(map[string]ReasonInfo)
+)
+
+// RegisterReason registers a reason into the catalog.
+// Registering an identical ReasonInfo again is a no-op, any other registration of an already registered reason,
+// from the same source or not, is a duplicate and an error is returned, the first registration is kept.
+func RegisterReason(info ReasonInfo) error {
+	catalogMu.Lock()
+	defer catalogMu.Unlock()
+	if exist, ok := catalog[info.Reason]; ok {
+		if exist == info {
+			return nil
+		}
+		return fmt.Errorf("xerrors: duplicate reason %q registered by %s and %s", info.Reason, exist.Source, info.Source)
+	}
+	catalog[info.Reason] = info
+	return nil
+}
+
+// MustRegisterReason is like RegisterReason but panics on duplicates,
+// so duplicate reasons across proto files are detected at init.
+func MustRegisterReason(info ReasonInfo) {
+	if err := RegisterReason(info); err != nil {
+		panic(err)
+	}
+}
+
+// LookupReason returns the registered info of a reason.
+func LookupReason(reason string) (ReasonInfo, bool) {
+	catalogMu.RLock()
+	defer catalogMu.RUnlock()
+	info, ok := catalog[reason]
+	return info, ok
+}
+
+// Reasons returns all registered reasons sorted by reason.
+func Reasons() []ReasonInfo {
+	catalogMu.RLock()
+	defer catalogMu.RUnlock()
+	infos := make([]ReasonInfo, 0, len(catalog))
+	for _, info := range catalog {
+		infos = append(infos, info)
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].Reason < infos[j].Reason
+	})
+	return infos
+}
+
+// ExportReasons returns the catalog as a json array sorted by reason.
+func ExportReasons() ([]byte, error) {
+	return xjson.Marshal(Reasons())
+}
@@ -0,0 +1,75 @@
+package xerrors
+
+import (
+	"path"
+	"strings"
+)
+
+// Category reason的分类，即reason中"."分隔的前缀，例如"FailedOperation"、"FailedOperation.User"
+// 作为errors.Is的target时匹配该分类及其下所有子reason：
+//
+//	errors.Is(NewReasonError("ResourceNotFound.User", ""), xerrors.ResourceNotFound) // true
+type Category string
+
+// 内置的reason分类，与protoc-gen-error的PrefixErrorReason一致
+const (
+	InternalError         Category = "InternalError"
+	InvalidParameter      Category = "InvalidParameter"
+	UnknownParameter      Category = "UnknownParameter"
+	AuthFailure           Category = "AuthFailure"
+	InvalidAction         Category = "InvalidAction"
+	UnauthorizedOperation Category = "UnauthorizedOperation"
+	ResourceNotFound      Category = "ResourceNotFound"
+	FailedOperation       Category = "FailedOperation"
+)
+
+// Error implements error interface, it returns the category itself.
+func (c Category) Error() string {
+	return string(c)
+}
+
+// Match reports whether reason is c or a child reason of c.
+func (c Category) Match(reason string) bool {
+	return reason == string(c) || strings.HasPrefix(reason, string(c)+".")
+}
+
+// IsPrefix reports whether the reason of err is prefix or a child reason of prefix,
+// e.g. IsPrefix(err, "FailedOperation") matches "FailedOperation.UserNotFound".
+func IsPrefix(err error, prefix string) bool {
+	return err != nil && Category(prefix).Match(Parse(err).Reason)
+}
+
+// MatchPattern reports whether reason matches the glob pattern, segments are separated by ".".
+// In a segment "*" matches any sequence of characters and "?" a single character as path.Match,
+// a "**" segment matches zero or more segments, e.g. "FailedOperation.*", "*.User*", "InvalidParameter.**".
+// A malformed pattern matches nothing.
+func MatchPattern(pattern, reason string) bool {
+	return matchSegments(strings.Split(pattern, "."), strings.Split(reason, "."))
+}
+
+// IsMatch reports whether the reason of err matches the glob pattern described in MatchPattern.
+func IsMatch(err error, pattern string) bool {
+	return err != nil && MatchPattern(pattern, Parse(err).Reason)
+}
+
+// matchSegments 逐段匹配，"**"段回溯尝试匹配0到多段
+func matchSegments(pattern, reason []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(reason); i++ {
+				if matchSegments(pattern[1:], reason[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(reason) == 0 {
+			return false
+		}
+		if ok, err := path.Match(pattern[0], reason[0]); err != nil || !ok {
+			return false
+		}
+		pattern, reason = pattern[1:], reason[1:]
+	}
+	return len(reason) == 0
+}
@@ -0,0 +1,83 @@
+package xerrors
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ContextExtractor 从context中取出请求级别的字段，例如request id、user id，返回值合并到Metadata
+type ContextExtractor func(ctx context.Context) map[string]interface{}
+
+var (
+	extractorsMu sync.RWMutex
+	extractors   []ContextExtractor
+)
+
+// RegisterContextExtractor registers an extractor used by the *Ctx constructors.
+// Extractors are applied in registration order, a later one overrides the same key of an earlier one.
+func RegisterContextExtractor(e ContextExtractor) {
+	extractorsMu.Lock()
+	defer extractorsMu.Unlock()
+	extractors = append(extractors, e)
+}
+
+// ContextValue returns an extractor setting the metadata key to ctx.Value(ctxKey) if it is not nil.
+func ContextValue(key string, ctxKey interface{}) ContextExtractor {
+	return func(ctx context.Context) map[string]interface{} {
+		if v := ctx.Value(ctxKey); v != nil {
+			return map[string]interface{}{key: v}
+		}
+		return nil
+	}
+}
+
+// TraceID returns an extractor setting the metadata key to the trace id of the OpenTelemetry span in ctx.
+func TraceID(key string) ContextExtractor {
+	return func(ctx context.Context) map[string]interface{} {
+		if sc := trace.SpanContextFromContext(ctx); sc.HasTraceID() {
+			return map[string]interface{}{key: sc.TraceID().String()}
+		}
+		return nil
+	}
+}
+
+// NewReasonErrorCtx is like NewReasonError, the metadata of the registered extractors is merged from ctx.
+func NewReasonErrorCtx(ctx context.Context, reason, message string) *ReasonError {
+	return notify(StageNew, withContext(ctx, newReasonError(1, nil, reason, message)))
+}
+
+// NewReasonErrorfCtx NewReasonErrorCtx(ctx, reason, fmt.Sprintf(format, args...))
+func NewReasonErrorfCtx(ctx context.Context, reason, format string, args ...interface{}) *ReasonError {
+	return notify(StageNew, withContext(ctx, newReasonError(1, nil, reason, fmt.Sprintf(format, args...))))
+}
+
+// WrapReasonCtx is like WrapReason, the metadata of the registered extractors is merged from ctx.
+func WrapReasonCtx(ctx context.Context, err error, reason, message string) *ReasonError {
+	return notify(StageNew, withContext(ctx, newReasonError(1, err, reason, message)))
+}
+
+// WrapReasonfCtx WrapReasonCtx(ctx, err, reason, fmt.Sprintf(format, args...))
+func WrapReasonfCtx(ctx context.Context, err error, reason, format string, args ...interface{}) *ReasonError {
+	return notify(StageNew, withContext(ctx, newReasonError(1, err, reason, fmt.Sprintf(format, args...))))
+}
+
+// withContext 把extractor取出的字段写入新建的se，se尚未返回给调用方，可以直接修改
+func withContext(ctx context.Context, se *ReasonError) *ReasonError {
+	if ctx == nil {
+		return se
+	}
+	extractorsMu.RLock()
+	exs := extractors
+	extractorsMu.RUnlock()
+	for _, e := range exs {
+		for k, v := range e(ctx) {
+			if v != nil {
+				se.Metadata[k] = normalizeValue(v)
+			}
+		}
+	}
+	return se
+}
@@ -0,0 +1,52 @@
+package xerrors
+
+import "github.com/codermuhao/tools/xjson"
+
+const (
+	// DecodeFieldKey 解码出错字段的json路径所在的元数据key
+	DecodeFieldKey = "field"
+	// DecodeErrorsKey 多个解码错误时全部错误所在的元数据key，每项包含field、expected、value、offset、message
+	DecodeErrorsKey = "errors"
+)
+
+// FromDecodeError converts a decode error of xjson to an InvalidParameter ReasonError.
+// The reason is "InvalidParameter.<field>" of the first field error, where field is FieldError.Field,
+// e.g. "InvalidParameter.inners.inner_bool" or "InvalidParameter.counts" for a bad map value.
+// It is built from proto field names only, so the reason stays a bounded metric label,
+// unknown fields and errors of the whole input are plain "InvalidParameter".
+// The path, expected type, value snippet and offset of the first error are kept in the metadata,
+// all the errors are kept in DecodeErrorsKey when there are more than one.
+func FromDecodeError(de *xjson.DecodeError) *ReasonError {
+	if de == nil || len(de.Errors) == 0 {
+		return nil
+	}
+	fe := de.Errors[0]
+	reason := string(InvalidParameter)
+	if len(fe.Field) > 0 {
+		reason += "." + fe.Field
+	}
+	md := fieldErrorMetadata(fe)
+	if len(de.Errors) > 1 {
+		errs := make([]interface{}, 0, len(de.Errors))
+		for _, fe := range de.Errors {
+			errs = append(errs, fieldErrorMetadata(fe))
+		}
+		md[DecodeErrorsKey] = errs
+	}
+	delete(md, "message")
+	se := newReasonError(0, de, reason, fe.Message)
+	for k, v := range md {
+		se.Metadata[k] = normalizeValue(v)
+	}
+	return se
+}
+
+func fieldErrorMetadata(fe *xjson.FieldError) map[string]interface{} {
+	return map[string]interface{}{
+		DecodeFieldKey: fe.Path,
+		"expected":     fe.Expected,
+		"value":        fe.Value,
+		"offset":       fe.Offset,
+		"message":      fe.Message,
+	}
+}
@@ -0,0 +1,71 @@
+package xerrors
+
+import (
+	"encoding/json"
+	"sort"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// jsonAPI 与xjson相同的json配置，保证手写编码的结果与xjson.Marshal一致
+var jsonAPI = jsoniter.ConfigCompatibleWithStandardLibrary
+
+// encode 手写ReasonError的json编码，避免每次Error()都走反射
+// 输出与xjson.Marshal(e)完全一致：字段顺序msg、reason、continue、metadata、retry，metadata的key排序，转义html字符
+func (e *ReasonError) encode(stream *jsoniter.Stream) {
+	stream.WriteRaw(`{"msg":`)
+	stream.WriteStringWithHTMLEscaped(e.Msg)
+	stream.WriteRaw(`,"reason":`)
+	stream.WriteStringWithHTMLEscaped(e.Reason)
+	stream.WriteRaw(`,"continue":`)
+	stream.WriteBool(e.Continue)
+	stream.WriteRaw(`,"metadata":`)
+	encodeMetadata(stream, e.Metadata)
+	if e.Retry != nil {
+		stream.WriteRaw(`,"retry":{"class":`)
+		stream.WriteStringWithHTMLEscaped(e.Retry.Class.String())
+		if e.Retry.After != 0 {
+			stream.WriteRaw(`,"after":`)
+			stream.WriteInt64(int64(e.Retry.After))
+		}
+		stream.WriteObjectEnd()
+	}
+	stream.WriteObjectEnd()
+}
+
+func encodeMetadata(stream *jsoniter.Stream, md Metadata) {
+	if md == nil {
+		stream.WriteNil()
+		return
+	}
+	keys := make([]string, 0, len(md))
+	for k := range md {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	stream.WriteObjectStart()
+	for i, k := range keys {
+		if i > 0 {
+			stream.WriteMore()
+		}
+		stream.WriteStringWithHTMLEscaped(k)
+		stream.WriteRaw(":")
+		switch v := md[k].(type) {
+		case string:
+			stream.WriteStringWithHTMLEscaped(v)
+		case bool:
+			stream.WriteBool(v)
+		case json.Number:
+			if len(v) == 0 {
+				stream.WriteRaw("0")
+			} else {
+				stream.WriteRaw(string(v))
+			}
+		case nil:
+			stream.WriteNil()
+		default:
+			stream.WriteVal(v)
+		}
+	}
+	stream.WriteObjectEnd()
+}
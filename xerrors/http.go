@@ -0,0 +1,105 @@
+package xerrors
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// DefaultHTTPStatus is the http status of errors whose reason is not registered.
+var DefaultHTTPStatus = http.StatusInternalServerError
+
+var (
+	httpStatusMu sync.RWMutex
+	// httpStatus reason或reason前缀(reason.proto中的PrefixErrorReason)到http status的映射
+	httpStatus = map[string]int{
+		"InternalError":         http.StatusInternalServerError,
+		"InvalidParameter":      http.StatusBadRequest,
+		"UnknownParameter":      http.StatusBadRequest,
+		"AuthFailure":           http.StatusUnauthorized,
+		"InvalidAction":         http.StatusBadRequest,
+		"UnauthorizedOperation": http.StatusForbidden,
+		"ResourceNotFound":      http.StatusNotFound,
+		"FailedOperation":       http.StatusBadRequest,
+	}
+)
+
+// RegisterHTTPStatus maps a reason, or a reason prefix such as "ResourceNotFound", to a http status.
+func RegisterHTTPStatus(reason string, code int) {
+	httpStatusMu.Lock()
+	defer httpStatusMu.Unlock()
+	httpStatus[reason] = code
+}
+
+// HTTPStatus returns the http status of the error.
+// The exact reason is tried first, then its dotted prefixes from the longest one,
+// DefaultHTTPStatus is returned if nothing matches. A nil error is http.StatusOK.
+func HTTPStatus(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+	return reasonHTTPStatus(Parse(err).Reason)
+}
+
+func reasonHTTPStatus(reason string) int {
+	httpStatusMu.RLock()
+	defer httpStatusMu.RUnlock()
+	code := DefaultHTTPStatus
+	matchReason(reason, func(key string) bool {
+		c, ok := httpStatus[key]
+		if ok {
+			code = c
+		}
+		return ok
+	})
+	return code
+}
+
+// httpReason http status到reason的反查，用于只有status没有reason的外部错误
+// 同一个status对应多个reason时优先使用defaultHTTPReasons中的reason，否则取字典序最小的一个
+func httpReason(code int) (string, bool) {
+	httpStatusMu.RLock()
+	defer httpStatusMu.RUnlock()
+	if reason, ok := defaultHTTPReasons[code]; ok && httpStatus[reason] == code {
+		return reason, true
+	}
+	var found string
+	for reason, c := range httpStatus {
+		if c == code && (len(found) == 0 || reason < found) {
+			found = reason
+		}
+	}
+	return found, len(found) > 0
+}
+
+// defaultHTTPReasons httpReason反查时各status优先使用的reason
+var defaultHTTPReasons = map[int]string{
+	http.StatusBadRequest:          "InvalidParameter",
+	http.StatusUnauthorized:        "AuthFailure",
+	http.StatusForbidden:           "UnauthorizedOperation",
+	http.StatusNotFound:            "ResourceNotFound",
+	http.StatusInternalServerError: "InternalError",
+}
+
+// matchReason 依次用reason本身及其由长到短的前缀调用fn，直到fn返回true
+// 例如"FailedOperation.User.NotFound"依次尝试"FailedOperation.User.NotFound"、"FailedOperation.User"、"FailedOperation"
+func matchReason(reason string, fn func(key string) bool) bool {
+	for key := reason; ; {
+		if fn(key) {
+			return true
+		}
+		i := strings.LastIndexByte(key, '.')
+		if i < 0 {
+			return false
+		}
+		key = key[:i]
+	}
+}
+
+// reasonPrefix reason第一个"."之前的部分，没有"."时为reason本身
+func reasonPrefix(reason string) string {
+	if i := strings.IndexByte(reason, '.'); i >= 0 {
+		return reason[:i]
+	}
+	return reason
+}
@@ -0,0 +1,102 @@
+package xerrors
+
+import (
+	"bytes"
+	"sync"
+	"text/template"
+
+	"golang.org/x/text/language"
+)
+
+// localeMessages 某个reason下各语言的消息模板
+type localeMessages struct {
+	tags      []language.Tag
+	templates []*template.Template
+	matcher   language.Matcher
+}
+
+var (
+	messagesMu sync.RWMutex
+	// messages reason或reason前缀到多语言消息模板的映射
+	messages = make(map[string]*localeMessages)
+)
+
+// RegisterMessage registers the message of a reason, or a reason prefix, for the language tag such as "en" or "zh-CN".
+// The message is a text/template executed with the error's Metadata, e.g. "user {{.uid}} not found".
+func RegisterMessage(reason, lang, message string) error {
+	tag, err := language.Parse(lang)
+	if err != nil {
+		return err
+	}
+	tmpl, err := template.New(reason + "/" + tag.String()).Parse(message)
+	if err != nil {
+		return err
+	}
+	messagesMu.Lock()
+	defer messagesMu.Unlock()
+	lm, ok := messages[reason]
+	if !ok {
+		lm = new(localeMessages)
+		messages[reason] = lm
+	}
+	replaced := false
+	for i, t := range lm.tags {
+		if t == tag {
+			lm.templates[i], replaced = tmpl, true
+		}
+	}
+	if !replaced {
+		lm.tags = append(lm.tags, tag)
+		lm.templates = append(lm.templates, tmpl)
+	}
+	lm.matcher = language.NewMatcher(lm.tags)
+	return nil
+}
+
+// MustRegisterMessage is like RegisterMessage but panics if the language tag or the message is invalid.
+func MustRegisterMessage(reason, lang, message string) {
+	if err := RegisterMessage(reason, lang, message); err != nil {
+		panic(err)
+	}
+}
+
+// Localize returns a copy of the error whose message is translated for lang.
+// lang may be a single language tag or an Accept-Language header value.
+// The exact reason is tried first, then its dotted prefixes from the longest one,
+// the message is kept as is if no registered language matches.
+func Localize(err error, lang string) *ReasonError {
+	if err == nil {
+		return nil
+	}
+	se := Parse(err)
+	tags, _, perr := language.ParseAcceptLanguage(lang)
+	if perr != nil || len(tags) == 0 {
+		return se
+	}
+	messagesMu.RLock()
+	var tmpl *template.Template
+	matchReason(se.Reason, func(key string) bool {
+		lm, ok := messages[key]
+		if !ok {
+			return false
+		}
+		// 该key没有匹配的语言时继续尝试更短的前缀
+		_, i, conf := lm.matcher.Match(tags...)
+		if conf == language.No {
+			return false
+		}
+		tmpl = lm.templates[i]
+		return true
+	})
+	messagesMu.RUnlock()
+	if tmpl == nil {
+		return se
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, se.Metadata); err != nil {
+		return se
+	}
+	le := se.clone()
+	le.Msg = buf.String()
+	return le
+}
@@ -0,0 +1,194 @@
+package xerrors
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+)
+
+// Metadata ReasonError的元数据
+//
+// 为了保证Error()/Parse以及grpc ErrorInfo转换前后数据完全一致，写入时统一转换为以下形式：
+//
+//	string, bool       原样保存
+//	整数、浮点数        json.Number，十进制表示，int64/uint64不丢精度
+//	time.Duration      time.Duration.String()的结果，例如"1.5s"
+//	其他类型(struct等)  json编码后再解码得到的map[string]interface{}/[]interface{}，数字为json.Number
+//
+// 读取时请使用GetString/GetInt/GetBool/GetDuration/Decode，不要直接修改map，
+// ReasonError的With*方法都会返回新的error，不会修改原有的Metadata。
+type Metadata map[string]interface{}
+
+// GetString returns the string value of key.
+func (m Metadata) GetString(key string) (string, bool) {
+	s, ok := m[key].(string)
+	return s, ok
+}
+
+// GetInt returns the integer value of key, numeric strings are accepted.
+func (m Metadata) GetInt(key string) (int64, bool) {
+	switch v := m[key].(type) {
+	case json.Number:
+		i, err := v.Int64()
+		return i, err == nil
+	case string:
+		i, err := strconv.ParseInt(v, 10, 64)
+		return i, err == nil
+	case int:
+		return int64(v), true
+	case int64:
+		return v, true
+	case int32:
+		return int64(v), true
+	case float64:
+		return int64(v), v == math.Trunc(v)
+	}
+	return 0, false
+}
+
+// GetBool returns the bool value of key, "true"/"false" strings are accepted.
+func (m Metadata) GetBool(key string) (bool, bool) {
+	switch v := m[key].(type) {
+	case bool:
+		return v, true
+	case string:
+		b, err := strconv.ParseBool(v)
+		return b, err == nil
+	}
+	return false, false
+}
+
+// GetDuration returns the duration value of key.
+// Strings are parsed by time.ParseDuration, numbers are taken as nanoseconds.
+func (m Metadata) GetDuration(key string) (time.Duration, bool) {
+	switch v := m[key].(type) {
+	case string:
+		d, err := time.ParseDuration(v)
+		return d, err == nil
+	case time.Duration:
+		return v, true
+	}
+	if i, ok := m.GetInt(key); ok {
+		return time.Duration(i), true
+	}
+	return 0, false
+}
+
+// Decode decodes the value of key into v, which is usually a pointer to struct.
+func (m Metadata) Decode(key string, v interface{}) error {
+	val, ok := m[key]
+	if !ok {
+		return fmt.Errorf("xerrors: metadata %q not found", key)
+	}
+	data, err := json.Marshal(val)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, numbers are decoded as json.Number.
+func (m *Metadata) UnmarshalJSON(data []byte) error {
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	var md map[string]interface{}
+	if err := d.Decode(&md); err != nil {
+		return err
+	}
+	*m = md
+	return nil
+}
+
+// clone 复制一份Metadata，值本身已经规范化，浅拷贝即可
+func (m Metadata) clone() Metadata {
+	md := make(Metadata, len(m))
+	for k, v := range m {
+		md[k] = v
+	}
+	return md
+}
+
+// normalizeValue 把元数据的值转换为Metadata文档中约定的形式
+func normalizeValue(v interface{}) interface{} {
+	switch x := v.(type) {
+	case nil, string, bool, json.Number:
+		return v
+	case time.Duration:
+		return x.String()
+	case int:
+		return json.Number(strconv.FormatInt(int64(x), 10))
+	case int8:
+		return json.Number(strconv.FormatInt(int64(x), 10))
+	case int16:
+		return json.Number(strconv.FormatInt(int64(x), 10))
+	case int32:
+		return json.Number(strconv.FormatInt(int64(x), 10))
+	case int64:
+		return json.Number(strconv.FormatInt(x, 10))
+	case uint:
+		return json.Number(strconv.FormatUint(uint64(x), 10))
+	case uint8:
+		return json.Number(strconv.FormatUint(uint64(x), 10))
+	case uint16:
+		return json.Number(strconv.FormatUint(uint64(x), 10))
+	case uint32:
+		return json.Number(strconv.FormatUint(uint64(x), 10))
+	case uint64:
+		return json.Number(strconv.FormatUint(x, 10))
+	case float32:
+		if !math.IsNaN(float64(x)) && !math.IsInf(float64(x), 0) {
+			return json.Number(strconv.FormatFloat(float64(x), 'g', -1, 32))
+		}
+	case float64:
+		if !math.IsNaN(x) && !math.IsInf(x, 0) {
+			return json.Number(strconv.FormatFloat(x, 'g', -1, 64))
+		}
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			break
+		}
+		d := json.NewDecoder(bytes.NewReader(data))
+		d.UseNumber()
+		var val interface{}
+		if err := d.Decode(&val); err == nil {
+			return val
+		}
+	}
+	return fmt.Sprint(v)
+}
+
+// WithString returns a copy of the error with the string metadata.
+func (e *ReasonError) WithString(key, value string) *ReasonError {
+	return e.withValue(key, value)
+}
+
+// WithInt returns a copy of the error with the integer metadata.
+func (e *ReasonError) WithInt(key string, value int64) *ReasonError {
+	return e.withValue(key, value)
+}
+
+// WithBool returns a copy of the error with the bool metadata.
+func (e *ReasonError) WithBool(key string, value bool) *ReasonError {
+	return e.withValue(key, value)
+}
+
+// WithDuration returns a copy of the error with the duration metadata.
+func (e *ReasonError) WithDuration(key string, value time.Duration) *ReasonError {
+	return e.withValue(key, value)
+}
+
+// WithValue returns a copy of the error with the metadata of any json encodable value, e.g. a struct.
+// Read it back by Metadata.Decode.
+func (e *ReasonError) WithValue(key string, value interface{}) *ReasonError {
+	return e.withValue(key, value)
+}
+
+func (e *ReasonError) withValue(key string, value interface{}) *ReasonError {
+	se := e.clone()
+	se.Metadata[key] = normalizeValue(value)
+	return se
+}
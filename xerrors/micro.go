@@ -0,0 +1,86 @@
+package xerrors
+
+import (
+	"net/http"
+
+	"github.com/codermuhao/tools/xjson"
+
+	microerrors "github.com/asim/go-micro/v3/errors"
+)
+
+type microOptions struct {
+	id   string
+	code func(reason string) int32
+}
+
+// MicroOption go-micro错误转换的配置项
+type MicroOption func(*microOptions)
+
+// WithMicroID sets the Id of the go-micro error, usually the service name.
+func WithMicroID(id string) MicroOption {
+	return func(o *microOptions) {
+		o.id = id
+	}
+}
+
+// WithMicroCode sets the reason to go-micro code mapping, the default one is the http status registry.
+func WithMicroCode(f func(reason string) int32) MicroOption {
+	return func(o *microOptions) {
+		o.code = f
+	}
+}
+
+// ToMicroError converts an error to a go-micro error.
+// The Detail is the json form of the *ReasonError, so Continue and Metadata are kept,
+// Code and Status come from the reason. go-micro errors are returned as is,
+// unless a *ReasonError wraps them, e.g. WrapReason(me, ...), then the outer reason wins.
+func ToMicroError(err error, opts ...MicroOption) *microerrors.Error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := findReasonError(err); !ok {
+		if me := new(microerrors.Error); As(err, &me) {
+			return me
+		}
+	}
+	o := &microOptions{code: func(reason string) int32 {
+		return int32(reasonHTTPStatus(reason))
+	}}
+	for _, opt := range opts {
+		opt(o)
+	}
+	se := Parse(err)
+	code := o.code(se.Reason)
+	return &microerrors.Error{
+		Id:     o.id,
+		Code:   code,
+		Detail: se.Error(),
+		Status: http.StatusText(int(code)),
+	}
+}
+
+// MicroIDMetadataKey FromMicroError保存go-micro错误Id的元数据key
+const MicroIDMetadataKey = "micro_id"
+
+// FromMicroError converts a go-micro error to *ReasonError.
+// The Detail is decoded as a *ReasonError, otherwise the Detail becomes the message,
+// the reason is looked up from the Code through the http status registry (e.g. 404 is ResourceNotFound,
+// UNKNOWN_ERROR if nothing matches), and the Id is kept in the metadata under MicroIDMetadataKey.
+func FromMicroError(me *microerrors.Error) *ReasonError {
+	if me == nil {
+		return nil
+	}
+	se := new(ReasonError)
+	if err := xjson.Unmarshal([]byte(me.Detail), &se); err == nil && len(se.Reason) > 0 {
+		return se
+	}
+	reason, ok := httpReason(int(me.Code))
+	if !ok {
+		reason = unknown
+	}
+	se = newReasonError(0, nil, reason, me.Detail)
+	if len(me.Id) > 0 {
+		se.Metadata[MicroIDMetadataKey] = me.Id
+	}
+	return se
+}
@@ -0,0 +1,130 @@
+package xerrors
+
+import (
+	"bytes"
+
+	"github.com/codermuhao/tools/xjson"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/status"
+
+	microerrors "github.com/asim/go-micro/v3/errors"
+)
+
+// MultiErrorItem MultiError中的一项，Key用于标识出错的对象，例如批量接口中的id
+type MultiErrorItem struct {
+	Key   string       `json:"key"`
+	Error *ReasonError `json:"error"`
+}
+
+// MultiError 多个ReasonError的集合，用于批量接口一次返回多个错误
+// 序列化为按添加顺序排列的json数组：[{"key":"1","error":{"msg":"","reason":"","continue":false,"metadata":{}}}]
+// MultiError不是并发安全的
+type MultiError struct {
+	Items []MultiErrorItem
+}
+
+// NewMultiError returns an empty MultiError.
+func NewMultiError() *MultiError {
+	return &MultiError{}
+}
+
+// Add appends err under key, err is parsed into *ReasonError. A nil err is ignored.
+func (m *MultiError) Add(key string, err error) *MultiError {
+	if err == nil {
+		return m
+	}
+	m.Items = append(m.Items, MultiErrorItem{Key: key, Error: Parse(err)})
+	return m
+}
+
+// Get returns the first error added under key, or nil.
+func (m *MultiError) Get(key string) *ReasonError {
+	for _, item := range m.Items {
+		if item.Key == key {
+			return item.Error
+		}
+	}
+	return nil
+}
+
+// Len returns the number of errors.
+func (m *MultiError) Len() int {
+	return len(m.Items)
+}
+
+// ErrorOrNil returns nil if there is no error, otherwise m itself.
+// Use it when returning a MultiError as error to avoid a non-nil error holding an empty MultiError.
+func (m *MultiError) ErrorOrNil() error {
+	if m == nil || len(m.Items) == 0 {
+		return nil
+	}
+	return m
+}
+
+// Error implements error interface
+func (m *MultiError) Error() string {
+	data, err := xjson.Marshal(m.Items)
+	if err != nil {
+		data, _ = xjson.Marshal([]MultiErrorItem{{Error: newReasonError(0, nil, unknown, err.Error())}})
+	}
+	return string(data)
+}
+
+// Unwrap returns the contained errors, so errors.Is and errors.As match any of them.
+func (m *MultiError) Unwrap() []error {
+	errs := make([]error, 0, len(m.Items))
+	for _, item := range m.Items {
+		errs = append(errs, item.Error)
+	}
+	return errs
+}
+
+// ParseMulti try to convert an error to *MultiError.
+// It accepts a wrapped *MultiError or its json form, any other error becomes a MultiError with
+// the single Parse result under an empty key.
+func ParseMulti(err error) *MultiError {
+	if err == nil {
+		return nil
+	}
+	if me := new(MultiError); errors.As(err, &me) {
+		return me
+	}
+	if me := parseMultiJSON(err.Error()); me != nil {
+		return me
+	}
+	return NewMultiError().Add("", err)
+}
+
+// findMultiError 按Parse的顺序查找错误树中的*MultiError，先遇到Parse会转换的错误时返回nil
+func findMultiError(err error) *MultiError {
+	var me *MultiError
+	walk(err, func(e error) bool {
+		switch x := e.(type) {
+		case *MultiError:
+			me = x
+			return true
+		case *ReasonError, *microerrors.Error, *xjson.DecodeError, interface{ GRPCStatus() *status.Status }:
+			return true
+		}
+		return false
+	})
+	return me
+}
+
+// parseMultiJSON 解析MultiError的json数组形式，失败返回nil
+func parseMultiJSON(s string) *MultiError {
+	data := bytes.TrimSpace([]byte(s))
+	if len(data) == 0 || data[0] != '[' {
+		return nil
+	}
+	var items []MultiErrorItem
+	if err := xjson.Unmarshal(data, &items); err != nil || len(items) == 0 {
+		return nil
+	}
+	for _, item := range items {
+		if item.Error == nil {
+			return nil
+		}
+	}
+	return &MultiError{Items: items}
+}
@@ -0,0 +1,60 @@
+package xerrors
+
+import "sync"
+
+// Stage 观察到ReasonError的阶段
+type Stage int
+
+const (
+	// StageNew the error is constructed by NewReasonError/NewReasonErrorf/WrapReason/WrapReasonf.
+	// Errors converted by Parse, FromStatus, FromMicroError or FromProblem are not observed.
+	// The error is observed before WithContinue, WithRetry, WithMetadata etc. are applied,
+	// so only its reason and message are final.
+	StageNew Stage = iota
+	// StageReturn the error is returned to the client, e.g. by the generated gin BFF.
+	StageReturn
+)
+
+// String returns the metric label value of the stage.
+func (s Stage) String() string {
+	if s == StageReturn {
+		return "return"
+	}
+	return "new"
+}
+
+// Observer is notified of ReasonErrors, it must not modify the error or construct ReasonErrors itself.
+type Observer func(stage Stage, se *ReasonError)
+
+var (
+	observersMu sync.RWMutex
+	observers   []Observer
+)
+
+// RegisterObserver registers an observer notified when ReasonErrors are constructed or returned.
+// Observers are called synchronously in registration order, keep them cheap.
+func RegisterObserver(o Observer) {
+	observersMu.Lock()
+	defer observersMu.Unlock()
+	observers = append(observers, o)
+}
+
+// ObserveReturn notifies the observers that err is returned to the client, err is parsed into *ReasonError.
+// A nil err is ignored.
+func ObserveReturn(err error) {
+	if err == nil {
+		return
+	}
+	notify(StageReturn, Parse(err))
+}
+
+// notify 通知所有observer，返回se本身
+func notify(stage Stage, se *ReasonError) *ReasonError {
+	observersMu.RLock()
+	obs := observers
+	observersMu.RUnlock()
+	for _, o := range obs {
+		o(stage, se)
+	}
+	return se
+}
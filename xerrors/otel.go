@@ -0,0 +1,100 @@
+package xerrors
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RecordOnSpan records err on the active span of ctx.
+// The reason, prefix, continue flag and metadata are set as "xerrors.*" attributes,
+// Sensitive metadata is masked, in the attributes as well as in the exception event,
+// which records err itself, keeping its type and wrap messages, with the message rendered by Redact.
+// Only non-Continue errors are recorded as an exception event
+// and set the span status to error, Continue errors leave the status untouched.
+func RecordOnSpan(ctx context.Context, err error) {
+	if err == nil {
+		return
+	}
+	span := trace.SpanFromContext(ctx)
+	if !span.IsRecording() {
+		return
+	}
+	se := Parse(err)
+	cont := Continue(err)
+	attrs := []attribute.KeyValue{
+		attribute.String("xerrors.reason", se.Reason),
+		attribute.String("xerrors.prefix", reasonPrefix(se.Reason)),
+		attribute.Bool("xerrors.continue", cont),
+	}
+	keys := make([]string, 0, len(se.Metadata))
+	for k := range se.Metadata {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		attrs = append(attrs, spanAttr("xerrors.metadata."+k, k, se.Metadata[k]))
+	}
+	span.SetAttributes(attrs...)
+	if cont {
+		return
+	}
+	// span.RecordError总是使用err.Error()作为exception.message，这里按相同的格式自行记录exception事件
+	span.AddEvent(semconv.ExceptionEventName, trace.WithAttributes(
+		semconv.ExceptionType(exceptionType(err)),
+		semconv.ExceptionMessage(exceptionMessage(err, se)),
+	))
+	span.SetStatus(codes.Error, se.Msg)
+}
+
+// exceptionType 和span.RecordError相同的exception.type
+func exceptionType(err error) string {
+	t := reflect.TypeOf(err)
+	if t.PkgPath() == "" && t.Name() == "" {
+		return t.String()
+	}
+	return t.PkgPath() + "." + t.Name()
+}
+
+// exceptionMessage err的消息，其中se的部分替换为Redact对外渲染的结果，外层的wrap消息保持不变
+func exceptionMessage(err error, se *ReasonError) string {
+	msg, raw := err.Error(), se.Error()
+	public := Redact(se, RenderPublic).Error()
+	if strings.Contains(msg, raw) {
+		return strings.Replace(msg, raw, public, -1)
+	}
+	if public == raw {
+		return msg
+	}
+	return public
+}
+
+// spanAttr 元数据转为span属性，数字保持为数字，struct等为json字符串
+func spanAttr(name, key string, v interface{}) attribute.KeyValue {
+	if MetadataSensitivity(key) == Sensitive {
+		return attribute.String(name, RedactedValue)
+	}
+	switch x := v.(type) {
+	case string:
+		return attribute.String(name, x)
+	case bool:
+		return attribute.Bool(name, x)
+	case json.Number:
+		if i, err := x.Int64(); err == nil {
+			return attribute.Int64(name, i)
+		}
+		if f, err := x.Float64(); err == nil {
+			return attribute.Float64(name, f)
+		}
+		return attribute.String(name, string(x))
+	}
+	data, _ := json.Marshal(v)
+	return attribute.String(name, string(data))
+}
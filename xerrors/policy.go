@@ -0,0 +1,138 @@
+package xerrors
+
+import (
+	"reflect"
+	"sync"
+)
+
+// Classifier 判断错误是否为业务正常流程错误(Continue)，例如无需告警的用户输入错误
+// se为err经过Parse后的结果，ok为false表示该Classifier不做判断，交给后续Classifier
+type Classifier func(err error, se *ReasonError) (cont bool, ok bool)
+
+var (
+	classifiersMu sync.RWMutex
+	classifiers   []Classifier
+)
+
+// RegisterClassifier registers a Classifier used by Continue.
+// Classifiers are tried in registration order, the first one returning ok decides.
+func RegisterClassifier(c Classifier) {
+	classifiersMu.Lock()
+	defer classifiersMu.Unlock()
+	classifiers = append(classifiers, c)
+}
+
+// ResetClassifiers removes all registered Classifiers, mainly for tests.
+func ResetClassifiers() {
+	classifiersMu.Lock()
+	defer classifiersMu.Unlock()
+	classifiers = nil
+}
+
+// ClassifyPrefix returns a Classifier deciding errors whose reason is prefix or starts with prefix+".".
+func ClassifyPrefix(prefix string, cont bool) Classifier {
+	return func(err error, se *ReasonError) (bool, bool) {
+		if Category(prefix).Match(se.Reason) {
+			return cont, true
+		}
+		return false, false
+	}
+}
+
+// ClassifyPattern returns a Classifier deciding errors whose reason matches the glob pattern described in MatchPattern.
+func ClassifyPattern(pattern string, cont bool) Classifier {
+	return func(err error, se *ReasonError) (bool, bool) {
+		if MatchPattern(pattern, se.Reason) {
+			return cont, true
+		}
+		return false, false
+	}
+}
+
+// ClassifyMetadata returns a Classifier deciding errors by the bool metadata of key.
+func ClassifyMetadata(key string) Classifier {
+	return func(err error, se *ReasonError) (bool, bool) {
+		return se.Metadata.GetBool(key)
+	}
+}
+
+// ClassifyIs returns a Classifier deciding errors matching target by errors.Is, e.g. context.Canceled.
+func ClassifyIs(target error, cont bool) Classifier {
+	return func(err error, se *ReasonError) (bool, bool) {
+		if Is(err, target) {
+			return cont, true
+		}
+		return false, false
+	}
+}
+
+// ClassifyType returns a Classifier deciding errors whose tree contains an error of the same type as prototype,
+// e.g. ClassifyType((*net.OpError)(nil), false). The tree is walked as Parse does, including Join and MultiError.
+func ClassifyType(prototype error, cont bool) Classifier {
+	t := reflect.TypeOf(prototype)
+	return func(err error, se *ReasonError) (bool, bool) {
+		if walk(err, func(e error) bool { return reflect.TypeOf(e) == t }) {
+			return cont, true
+		}
+		return false, false
+	}
+}
+
+// Continue reports whether err is a business-normal error.
+// A nil error is true, then the registered Classifiers decide, at last the 'continue' flag is returned.
+func Continue(err error) bool {
+	if err == nil {
+		return true
+	}
+	se := Parse(err)
+	classifiersMu.RLock()
+	defer classifiersMu.RUnlock()
+	for _, c := range classifiers {
+		if cont, ok := c(err, se); ok {
+			return cont
+		}
+	}
+	return se.Continue
+}
+
+// ShouldAlert reports whether err needs alerting, that is a non-nil error which is not Continue.
+func ShouldAlert(err error) bool {
+	return err != nil && !Continue(err)
+}
+
+// Level 错误对应的日志级别
+type Level int8
+
+const (
+	// LevelDebug nil error
+	LevelDebug Level = iota
+	// LevelInfo business-normal error
+	LevelInfo
+	// LevelError other errors
+	LevelError
+)
+
+// String returns the name of the level.
+func (l Level) String() string {
+	switch l {
+	case LevelDebug:
+		return "debug"
+	case LevelInfo:
+		return "info"
+	case LevelError:
+		return "error"
+	}
+	return "unknown"
+}
+
+// LogLevel returns the log level of err: debug for nil, info for Continue errors and error for the others.
+func LogLevel(err error) Level {
+	switch {
+	case err == nil:
+		return LevelDebug
+	case Continue(err):
+		return LevelInfo
+	default:
+		return LevelError
+	}
+}
@@ -0,0 +1,239 @@
+package xerrors
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/codermuhao/tools/xjson"
+)
+
+const (
+	// ProblemContentType RFC 7807 problem details的media type
+	ProblemContentType = "application/problem+json"
+	// JSONContentType 原有{msg, reason, continue, metadata}格式的content type
+	JSONContentType = "application/json; charset=utf-8"
+)
+
+// Problem RFC 7807 problem details
+// ReasonError的reason、continue、metadata、retry作为扩展成员保存在Extensions中
+type Problem struct {
+	Type       string
+	Title      string
+	Status     int
+	Detail     string
+	Instance   string
+	Extensions map[string]interface{}
+}
+
+type problemOptions struct {
+	typeBase string
+	instance string
+	mode     RenderMode
+	lang     string
+}
+
+// ProblemOption problem details转换的配置项
+type ProblemOption func(*problemOptions)
+
+// WithProblemTypeBase sets the base URI of the type member, the type is base+reason.
+// Without it the type is "about:blank" and the title is the http status text.
+func WithProblemTypeBase(base string) ProblemOption {
+	return func(o *problemOptions) {
+		o.typeBase = base
+	}
+}
+
+// WithProblemInstance sets the instance member, usually the request path.
+func WithProblemInstance(instance string) ProblemOption {
+	return func(o *problemOptions) {
+		o.instance = instance
+	}
+}
+
+// WithProblemMode sets the render mode of the metadata, the default is RenderPublic.
+func WithProblemMode(mode RenderMode) ProblemOption {
+	return func(o *problemOptions) {
+		o.mode = mode
+	}
+}
+
+// WithProblemLanguage localizes the rendered errors by Localize, lang may be an Accept-Language header value.
+func WithProblemLanguage(lang string) ProblemOption {
+	return func(o *problemOptions) {
+		o.lang = lang
+	}
+}
+
+func newProblemOptions(opts []ProblemOption) *problemOptions {
+	o := &problemOptions{mode: RenderPublic}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// render 按配置翻译并脱敏se
+func (o *problemOptions) render(se *ReasonError) *ReasonError {
+	if len(o.lang) > 0 {
+		se = Localize(se, o.lang)
+	}
+	return redact(se, o.mode)
+}
+
+// renderMulti 按配置翻译并脱敏me的每一项
+func (o *problemOptions) renderMulti(me *MultiError) *MultiError {
+	re := &MultiError{Items: make([]MultiErrorItem, 0, len(me.Items))}
+	for _, item := range me.Items {
+		re.Items = append(re.Items, MultiErrorItem{Key: item.Key, Error: o.render(item.Error)})
+	}
+	return re
+}
+
+// ToProblem converts an error to RFC 7807 problem details.
+// For a *MultiError the standard members come from its first item,
+// and all the items are in the "errors" extension member in the json array form of MultiError.
+func ToProblem(err error, opts ...ProblemOption) *Problem {
+	if err == nil {
+		return nil
+	}
+	o := newProblemOptions(opts)
+	se := o.render(Parse(err))
+	p := &Problem{
+		Type:     "about:blank",
+		Status:   reasonHTTPStatus(se.Reason),
+		Detail:   se.Msg,
+		Instance: o.instance,
+		Extensions: map[string]interface{}{
+			"reason":   se.Reason,
+			"continue": se.Continue,
+		},
+	}
+	p.Title = http.StatusText(p.Status)
+	if len(o.typeBase) > 0 {
+		p.Type, p.Title = o.typeBase+se.Reason, se.Reason
+	}
+	if len(se.Metadata) > 0 {
+		p.Extensions["metadata"] = se.Metadata
+	}
+	if se.Retry != nil {
+		p.Extensions["retry"] = se.Retry
+	}
+	if me := findMultiError(err); me != nil {
+		p.Extensions["errors"] = o.renderMulti(me).Items
+	}
+	return p
+}
+
+// FromProblem converts RFC 7807 problem details to *ReasonError.
+// The reason is the "reason" extension, or the type without the base set by WithProblemTypeBase.
+func FromProblem(p *Problem, opts ...ProblemOption) *ReasonError {
+	if p == nil {
+		return nil
+	}
+	o := newProblemOptions(opts)
+	ext := Metadata(p.Extensions)
+	reason, ok := ext.GetString("reason")
+	if !ok {
+		reason = unknown
+		if len(o.typeBase) > 0 && strings.HasPrefix(p.Type, o.typeBase) {
+			reason = strings.TrimPrefix(p.Type, o.typeBase)
+		}
+	}
+	se := newReasonError(0, nil, reason, p.Detail)
+	se.Continue, _ = ext.GetBool("continue")
+	_ = ext.Decode("metadata", &se.Metadata)
+	if _, ok := ext["retry"]; ok {
+		se.Retry = new(Retry)
+		if err := ext.Decode("retry", se.Retry); err != nil {
+			se.Retry = nil
+		}
+	}
+	return se
+}
+
+// ParseProblem parses an application/problem+json body into *ReasonError.
+func ParseProblem(data []byte, opts ...ProblemOption) (*ReasonError, error) {
+	p := new(Problem)
+	if err := p.UnmarshalJSON(data); err != nil {
+		return nil, err
+	}
+	return FromProblem(p, opts...), nil
+}
+
+// MarshalJSON implements json.Marshaler, extension members are at the same level as the standard ones.
+func (p *Problem) MarshalJSON() ([]byte, error) {
+	m := make(map[string]interface{}, len(p.Extensions)+5)
+	for k, v := range p.Extensions {
+		m[k] = v
+	}
+	m["type"] = p.Type
+	m["title"] = p.Title
+	m["status"] = p.Status
+	m["detail"] = p.Detail
+	if len(p.Instance) > 0 {
+		m["instance"] = p.Instance
+	}
+	return xjson.Marshal(m)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, unknown members are kept in Extensions.
+func (p *Problem) UnmarshalJSON(data []byte) error {
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	m := make(map[string]interface{})
+	if err := d.Decode(&m); err != nil {
+		return err
+	}
+	ext := Metadata(m)
+	p.Type, _ = ext.GetString("type")
+	p.Title, _ = ext.GetString("title")
+	status, _ := ext.GetInt("status")
+	p.Status = int(status)
+	p.Detail, _ = ext.GetString("detail")
+	p.Instance, _ = ext.GetString("instance")
+	for _, k := range []string{"type", "title", "status", "detail", "instance"} {
+		delete(m, k)
+	}
+	p.Extensions = m
+	return nil
+}
+
+// AcceptProblem reports whether an Accept header value accepts application/problem+json.
+func AcceptProblem(accept string) bool {
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil || mediaType != ProblemContentType {
+			continue
+		}
+		if q, err := strconv.ParseFloat(params["q"], 64); err == nil && q <= 0 {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+// MarshalHTTP renders err for an http response, negotiated by the Accept header:
+// application/problem+json if it is accepted, otherwise the legacy {msg, reason, continue, metadata} json.
+// Both shapes are rendered in RenderPublic mode unless WithProblemMode says otherwise.
+// A *MultiError is rendered with all its items, as the json array form in the legacy json,
+// and in the "errors" extension member in problem+json. The status comes from the first item.
+func MarshalHTTP(err error, accept string, opts ...ProblemOption) (status int, contentType string, body []byte) {
+	if err == nil {
+		return http.StatusOK, JSONContentType, nil
+	}
+	status = HTTPStatus(err)
+	if AcceptProblem(accept) {
+		body, _ = ToProblem(err, opts...).MarshalJSON()
+		return status, ProblemContentType, body
+	}
+	o := newProblemOptions(opts)
+	if me := findMultiError(err); me != nil {
+		return status, JSONContentType, []byte(o.renderMulti(me).Error())
+	}
+	return status, JSONContentType, []byte(o.render(Parse(err)).Error())
+}
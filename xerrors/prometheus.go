@@ -0,0 +1,50 @@
+package xerrors
+
+import (
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Collector 按reason统计ReasonError出现次数的prometheus collector
+//
+//	c := xerrors.NewCollector("")
+//	prometheus.MustRegister(c)
+//	xerrors.RegisterObserver(c.Observe)
+type Collector struct {
+	occurrences *prometheus.CounterVec
+}
+
+// NewCollector returns a Collector of the counter <namespace>_xerrors_reason_occurrences_total,
+// labeled by reason, prefix, continue and stage. The prefix is the part of the reason before the first dot.
+func NewCollector(namespace string) *Collector {
+	return &Collector{
+		occurrences: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "xerrors",
+			Name:      "reason_occurrences_total",
+			Help:      "Number of ReasonErrors by reason, prefix, continue and stage.",
+		}, []string{"reason", "prefix", "continue", "stage"}),
+	}
+}
+
+// Observe counts se, it is an Observer to be registered by RegisterObserver.
+// The continue label is decided by Continue, so the registered classifiers are taken into account.
+// It is empty at StageNew, since WithContinue is called after the error is constructed.
+func (c *Collector) Observe(stage Stage, se *ReasonError) {
+	cont := ""
+	if stage != StageNew {
+		cont = strconv.FormatBool(Continue(se))
+	}
+	c.occurrences.WithLabelValues(se.Reason, reasonPrefix(se.Reason), cont, stage.String()).Inc()
+}
+
+// Describe implements prometheus.Collector.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	c.occurrences.Describe(ch)
+}
+
+// Collect implements prometheus.Collector.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.occurrences.Collect(ch)
+}
@@ -1,6 +1,8 @@
 package xerrors
 
 import (
+	stderrors "errors"
+
 	"github.com/pkg/errors"
 )
 
@@ -64,20 +66,21 @@ func Cause(err error) error {
 	return errors.Cause(err)
 }
 
-// Is reports whether any error in err's chain matches target.
+// Is reports whether any error in err's tree matches target.
 //
-// The chain consists of err itself followed by the sequence of errors obtained by
-// repeatedly calling Unwrap.
+// The tree consists of err itself, followed by the errors obtained by repeatedly
+// calling its Unwrap() error or Unwrap() []error method, e.g. errors created by Join.
 //
 // An error is considered to match a target if it is equal to that target or if
 // it implements a method Is(error) bool such that Is(target) returns true.
 func Is(err, target error) bool { return errors.Is(err, target) }
 
-// As finds the first error in err's chain that matches target, and if so, sets
+// As finds the first error in err's tree that matches target, and if so, sets
 // target to that error value and returns true.
 //
-// The chain consists of err itself followed by the sequence of errors obtained by
-// repeatedly calling Unwrap.
+// The tree consists of err itself, followed by the errors obtained by repeatedly
+// calling its Unwrap() error or Unwrap() []error method. It is traversed depth first,
+// use Parse to get the outermost *ReasonError.
 //
 // An error matches target if the error's concrete value is assignable to the value
 // pointed to by target, or if the error has a method As(interface{}) bool such that
@@ -90,7 +93,28 @@ func As(err error, target interface{}) bool { return errors.As(err, target) }
 
 // Unwrap returns the result of calling the Unwrap method on err, if err's
 // type contains an Unwrap method returning error.
-// Otherwise, Unwrap returns nil.
+// Otherwise, Unwrap returns nil, use UnwrapAll for errors created by Join.
 func Unwrap(err error) error {
 	return errors.Unwrap(err)
 }
+
+// UnwrapAll returns the errors err directly wraps: the result of Unwrap() []error,
+// or of Unwrap() error as a single element slice. Otherwise, UnwrapAll returns nil.
+func UnwrapAll(err error) []error {
+	switch x := err.(type) {
+	case interface{ Unwrap() []error }:
+		return x.Unwrap()
+	case interface{ Unwrap() error }:
+		if e := x.Unwrap(); e != nil {
+			return []error{e}
+		}
+	}
+	return nil
+}
+
+// Join returns an error that wraps the given errors, nil errors are discarded.
+// Join returns nil if every value in errs is nil.
+// Parse on the joined error picks the *ReasonError closest to it, the earlier one in errs at the same depth.
+func Join(errs ...error) error {
+	return stderrors.Join(errs...)
+}
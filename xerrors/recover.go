@@ -0,0 +1,81 @@
+package xerrors
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+
+	"google.golang.org/grpc"
+)
+
+const (
+	// PanicReason 由panic转换的错误的reason
+	PanicReason = "InternalError.Panic"
+	// PanicMetadataKey panic值所在的元数据key，登记为Internal，对外渲染时删除
+	PanicMetadataKey = "panic"
+)
+
+func init() {
+	RegisterMetadataKey(PanicMetadataKey, Internal)
+}
+
+// FromPanic converts a recovered value to a ReasonError of PanicReason.
+// The panic value is kept in the Internal metadata PanicMetadataKey and, if it is an error, as the cause.
+// Called in the deferred function, the stack starts at the function that panicked.
+func FromPanic(v interface{}) *ReasonError {
+	cause, _ := v.(error)
+	se := newReasonError(1, cause, PanicReason, "internal error")
+	se.Metadata[PanicMetadataKey] = fmt.Sprint(v)
+	se.stack = se.stack.trimPanic()
+	return notify(StageNew, se)
+}
+
+// Recover recovers a panic into *errp, it must be deferred directly:
+//
+//	func handle() (err error) {
+//		defer xerrors.Recover(&err)
+//		...
+//	}
+func Recover(errp *error) {
+	if r := recover(); r != nil {
+		*errp = FromPanic(r)
+	}
+}
+
+// RecoverWith recovers a panic and passes the converted error to fn, it must be deferred directly,
+// e.g. defer xerrors.RecoverWith(func(se *xerrors.ReasonError) { errorFunc(c, se) }).
+func RecoverWith(fn func(se *ReasonError)) {
+	if r := recover(); r != nil {
+		fn(FromPanic(r))
+	}
+}
+
+// UnaryServerRecovery returns a grpc unary interceptor converting panics of the handler
+// into ReasonErrors, which reach the client as grpc status with ErrorInfo.
+func UnaryServerRecovery() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (rsp interface{}, err error) {
+		defer Recover(&err)
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerRecovery returns a grpc stream interceptor converting panics of the handler into ReasonErrors.
+func StreamServerRecovery() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler) (err error) {
+		defer Recover(&err)
+		return handler(srv, ss)
+	}
+}
+
+// trimPanic 去掉runtime.gopanic及之前的栈帧，使调用栈从发生panic的函数开始
+func (s *stack) trimPanic() *stack {
+	for i, pc := range *s {
+		if fn := runtime.FuncForPC(pc - 1); fn != nil && fn.Name() == "runtime.gopanic" {
+			st := (*s)[i+1:]
+			return &st
+		}
+	}
+	return s
+}
@@ -0,0 +1,95 @@
+package xerrors
+
+import (
+	"sync"
+)
+
+// Sensitivity 元数据key的敏感级别
+type Sensitivity int
+
+const (
+	// Public 对外可见，默认级别
+	Public Sensitivity = iota
+	// Internal 仅内部可见(日志、内部服务间grpc)，对外渲染时删除，例如sql、内部id
+	Internal
+	// Sensitive 敏感数据，对外渲染时打码，例如token、手机号
+	Sensitive
+)
+
+// RenderMode 错误的渲染模式
+type RenderMode int
+
+const (
+	// RenderInternal 内部渲染，保留全部元数据，Error()和GRPCStatus使用此模式
+	RenderInternal RenderMode = iota
+	// RenderPublic 对外渲染，删除Internal元数据，Sensitive元数据打码
+	RenderPublic
+)
+
+// RedactedValue Sensitive元数据对外渲染时的替换值
+const RedactedValue = "******"
+
+var (
+	sensitivitiesMu sync.RWMutex
+	sensitivities   = make(map[string]Sensitivity)
+)
+
+// RegisterMetadataKey marks the sensitivity of a metadata key, unregistered keys are Public.
+func RegisterMetadataKey(key string, s Sensitivity) {
+	sensitivitiesMu.Lock()
+	defer sensitivitiesMu.Unlock()
+	sensitivities[key] = s
+}
+
+// MetadataSensitivity returns the sensitivity of a metadata key.
+func MetadataSensitivity(key string) Sensitivity {
+	sensitivitiesMu.RLock()
+	defer sensitivitiesMu.RUnlock()
+	return sensitivities[key]
+}
+
+// Redact returns err as *ReasonError rendered for mode.
+// RenderInternal returns Parse(err), RenderPublic returns a copy without Internal metadata
+// and with Sensitive metadata replaced by RedactedValue.
+// Parse keeps only the first item of a *MultiError, use (*MultiError).Redact to render all of them.
+func Redact(err error, mode RenderMode) *ReasonError {
+	if err == nil {
+		return nil
+	}
+	return redact(Parse(err), mode)
+}
+
+func redact(se *ReasonError, mode RenderMode) *ReasonError {
+	if mode != RenderPublic {
+		return se
+	}
+	re := se.clone()
+	for k := range re.Metadata {
+		switch MetadataSensitivity(k) {
+		case Internal:
+			delete(re.Metadata, k)
+		case Sensitive:
+			re.Metadata[k] = RedactedValue
+		}
+	}
+	return re
+}
+
+// Render returns the json form of the error rendered for mode.
+func (e *ReasonError) Render(mode RenderMode) string {
+	return Redact(e, mode).Error()
+}
+
+// Redact returns a copy of m with every item rendered for mode as Redact does.
+func (m *MultiError) Redact(mode RenderMode) *MultiError {
+	re := &MultiError{Items: make([]MultiErrorItem, 0, len(m.Items))}
+	for _, item := range m.Items {
+		re.Items = append(re.Items, MultiErrorItem{Key: item.Key, Error: redact(item.Error, mode)})
+	}
+	return re
+}
+
+// Render returns the json array form of m rendered for mode.
+func (m *MultiError) Render(mode RenderMode) string {
+	return m.Redact(mode).Error()
+}
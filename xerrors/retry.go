@@ -0,0 +1,129 @@
+package xerrors
+
+import (
+	"time"
+)
+
+// RetryClass 错误的重试分类，取值与protoc-gen-error reason.proto中的RetryClass一致
+type RetryClass int32
+
+const (
+	// RetryUnspecified 未指定
+	RetryUnspecified RetryClass = 0
+	// Retryable 可以重试
+	Retryable RetryClass = 1
+	// Temporary 临时性错误，稍后重试可能成功
+	Temporary RetryClass = 2
+	// Permanent 永久性错误，重试无意义
+	Permanent RetryClass = 3
+	// RateLimited 被限流，应在RetryAfter之后重试
+	RateLimited RetryClass = 4
+)
+
+var retryClassNames = map[RetryClass]string{
+	RetryUnspecified: "",
+	Retryable:        "retryable",
+	Temporary:        "temporary",
+	Permanent:        "permanent",
+	RateLimited:      "rate_limited",
+}
+
+// String returns the name of the class.
+func (c RetryClass) String() string {
+	return retryClassNames[c]
+}
+
+// MarshalText implements encoding.TextMarshaler, the class is encoded by its name.
+func (c RetryClass) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, unknown names become RetryUnspecified.
+func (c *RetryClass) UnmarshalText(text []byte) error {
+	*c = RetryUnspecified
+	for k, v := range retryClassNames {
+		if v == string(text) {
+			*c = k
+		}
+	}
+	return nil
+}
+
+// Retry 错误的重试信息，json形式为{"class":"rate_limited","after":1000000000}，after单位为纳秒
+type Retry struct {
+	Class RetryClass    `json:"class"`
+	After time.Duration `json:"after,omitempty"`
+}
+
+// WithRetry returns a copy of the error with the retry class.
+func (e *ReasonError) WithRetry(class RetryClass) *ReasonError {
+	se := e.clone()
+	se.Retry = &Retry{Class: class}
+	return se
+}
+
+// WithRetryAfter returns a copy of the error classified as RateLimited, to be retried after d.
+func (e *ReasonError) WithRetryAfter(d time.Duration) *ReasonError {
+	se := e.clone()
+	se.Retry = &Retry{Class: RateLimited, After: d}
+	return se
+}
+
+// WithRetryPolicy returns a copy of the error with both the retry class and how long to wait before retrying.
+func (e *ReasonError) WithRetryPolicy(class RetryClass, after time.Duration) *ReasonError {
+	se := e.clone()
+	se.Retry = &Retry{Class: class, After: after}
+	return se
+}
+
+// Temporary reports whether the error is Temporary or RateLimited, the same as net.Error.
+func (e *ReasonError) Temporary() bool {
+	return e.retryClass() == Temporary || e.retryClass() == RateLimited
+}
+
+func (e *ReasonError) retryClass() RetryClass {
+	if e.Retry == nil {
+		return RetryUnspecified
+	}
+	return e.Retry.Class
+}
+
+// IsRetryable reports whether the error is Retryable, Temporary or RateLimited.
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	switch Parse(err).retryClass() {
+	case Retryable, Temporary, RateLimited:
+		return true
+	}
+	return false
+}
+
+// IsTemporary reports whether the error is Temporary or RateLimited.
+func IsTemporary(err error) bool {
+	if err == nil {
+		return false
+	}
+	return Parse(err).Temporary()
+}
+
+// IsPermanent reports whether the error is Permanent.
+func IsPermanent(err error) bool {
+	if err == nil {
+		return false
+	}
+	return Parse(err).retryClass() == Permanent
+}
+
+// RetryAfter returns how long to wait before retrying, ok is false if the error does not say.
+func RetryAfter(err error) (d time.Duration, ok bool) {
+	if err == nil {
+		return 0, false
+	}
+	se := Parse(err)
+	if se.Retry == nil || se.Retry.After <= 0 {
+		return 0, false
+	}
+	return se.Retry.After, true
+}
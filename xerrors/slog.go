@@ -0,0 +1,254 @@
+//go:build go1.21
+
+package xerrors
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// LogValue implements slog.LogValuer, the error is logged as a group of
+// reason, msg, continue, metadata, retry, stack and cause attributes.
+func (e *ReasonError) LogValue() slog.Value {
+	return slog.GroupValue(e.logAttrs()...)
+}
+
+func (e *ReasonError) logAttrs() []slog.Attr {
+	attrs := []slog.Attr{
+		slog.String("reason", e.Reason),
+		slog.String("msg", e.Msg),
+		slog.Bool("continue", e.Continue),
+	}
+	if len(e.Metadata) > 0 {
+		keys := make([]string, 0, len(e.Metadata))
+		for k := range e.Metadata {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		md := make([]slog.Attr, 0, len(keys))
+		for _, k := range keys {
+			md = append(md, slog.Any(k, logMetadataValue(e.Metadata[k])))
+		}
+		attrs = append(attrs, slog.Attr{Key: "metadata", Value: slog.GroupValue(md...)})
+	}
+	if e.Retry != nil {
+		attrs = append(attrs, slog.String("retry", e.Retry.Class.String()))
+		if e.Retry.After > 0 {
+			attrs = append(attrs, slog.Duration("retry_after", e.Retry.After))
+		}
+	}
+	if st := logStack(e.StackTrace()); len(st) > 0 {
+		attrs = append(attrs, slog.Any("stack", st))
+	}
+	if e.cause != nil {
+		attrs = append(attrs, slog.String("cause", e.cause.Error()))
+	}
+	return attrs
+}
+
+// logMetadataValue json.Number转为int64或float64，便于日志输出为数字
+func logMetadataValue(v interface{}) interface{} {
+	n, ok := v.(json.Number)
+	if !ok {
+		return v
+	}
+	if i, err := n.Int64(); err == nil {
+		return i
+	}
+	if f, err := n.Float64(); err == nil {
+		return f
+	}
+	return string(n)
+}
+
+// logStack 调用栈转为"函数 文件:行号"形式的字符串列表
+func logStack(st errors.StackTrace) []string {
+	frames := make([]string, 0, len(st))
+	for _, f := range st {
+		frames = append(frames, strings.Replace(fmt.Sprintf("%+v", f), "\n\t", " ", 1))
+	}
+	return frames
+}
+
+// errorValuer 把任意error包装为slog.LogValuer
+type errorValuer struct {
+	err error
+}
+
+// LogValue implements slog.LogValuer.
+// An error chain containing a *ReasonError is logged as its attributes plus the "wrap" messages around it,
+// other errors are logged as "error" and the stack of pkg/errors if there is one.
+func (v errorValuer) LogValue() slog.Value {
+	if v.err == nil {
+		return slog.Value{}
+	}
+	if se, ok := findReasonError(v.err); ok {
+		attrs := se.logAttrs()
+		if wrap := wrapMessages(v.err, se); len(wrap) > 0 {
+			attrs = append(attrs, slog.String("wrap", strings.Join(wrap, ": ")))
+		}
+		return slog.GroupValue(attrs...)
+	}
+	attrs := []slog.Attr{slog.String("error", v.err.Error())}
+	var st interface{ StackTrace() errors.StackTrace }
+	if errors.As(v.err, &st) {
+		attrs = append(attrs, slog.Any("stack", logStack(st.StackTrace())))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// wrapMessages 按walk的顺序找到err到target的路径，由外到内收集路径上各层包装自身的消息
+// Join、MultiError等多错误节点没有自身的消息，跳过
+func wrapMessages(err, target error) []string {
+	type node struct {
+		err    error
+		parent int
+	}
+	nodes := []node{{err: err, parent: -1}}
+	found := -1
+	for i := 0; i < len(nodes) && found < 0; i++ {
+		if nodes[i].err == target {
+			found = i
+			continue
+		}
+		for _, e := range UnwrapAll(nodes[i].err) {
+			if e != nil {
+				nodes = append(nodes, node{err: e, parent: i})
+			}
+		}
+	}
+	var path []error
+	for i := found; i >= 0; i = nodes[i].parent {
+		path = append([]error{nodes[i].err}, path...)
+	}
+	var msgs []string
+	for i := 0; i+1 < len(path); i++ {
+		if _, ok := path[i].(interface{ Unwrap() []error }); ok {
+			continue
+		}
+		if msg := wrapMessage(path[i].Error(), path[i+1].Error()); len(msg) > 0 {
+			msgs = append(msgs, msg)
+		}
+	}
+	return msgs
+}
+
+// wrapMessage 去掉outer中内层错误的消息inner，得到包装自身的消息，例如"handler: inner"为"handler"
+func wrapMessage(outer, inner string) string {
+	if outer == inner {
+		return ""
+	}
+	if strings.HasSuffix(outer, ": "+inner) {
+		return strings.TrimSuffix(outer, ": "+inner)
+	}
+	return strings.Trim(strings.Replace(outer, inner, "", 1), " :")
+}
+
+// LogValuer returns a slog.LogValuer logging err, including errors wrapped by Wrap, as structured attributes.
+func LogValuer(err error) slog.LogValuer {
+	return errorValuer{err: err}
+}
+
+// Attr returns an "error" attribute of err, e.g. logger.Error("call failed", xerrors.Attr(err)).
+func Attr(err error) slog.Attr {
+	return slog.Any("error", LogValuer(err))
+}
+
+// SlogLevel returns the slog level of l.
+func (l Level) SlogLevel() slog.Level {
+	switch l {
+	case LevelDebug:
+		return slog.LevelDebug
+	case LevelInfo:
+		return slog.LevelInfo
+	}
+	return slog.LevelError
+}
+
+// continueHandler 把携带Continue错误的日志降级为info
+// err为WithAttrs中的第一个error，按属性的输出顺序排在record的属性之前
+type continueHandler struct {
+	h   slog.Handler
+	err error
+}
+
+// NewSlogHandler returns a slog.Handler middleware, records above info level carrying a Continue error
+// attribute are downgraded to info level before being passed to h. The first error decides, including
+// errors added by Logger.With and errors inside groups.
+func NewSlogHandler(h slog.Handler) slog.Handler {
+	return &continueHandler{h: h}
+}
+
+// Enabled implements slog.Handler.
+func (c *continueHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return c.h.Enabled(ctx, level)
+}
+
+// Handle implements slog.Handler.
+func (c *continueHandler) Handle(ctx context.Context, r slog.Record) error {
+	if r.Level <= slog.LevelInfo {
+		return c.h.Handle(ctx, r)
+	}
+	err := c.err
+	if err == nil {
+		r.Attrs(func(a slog.Attr) bool {
+			err = attrError(a.Value)
+			return err == nil
+		})
+	}
+	if err == nil || !Continue(err) {
+		return c.h.Handle(ctx, r)
+	}
+	if !c.h.Enabled(ctx, slog.LevelInfo) {
+		return nil
+	}
+	r = r.Clone()
+	r.Level = slog.LevelInfo
+	return c.h.Handle(ctx, r)
+}
+
+// WithAttrs implements slog.Handler.
+func (c *continueHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	err := c.err
+	for _, a := range attrs {
+		if err != nil {
+			break
+		}
+		err = attrError(a.Value)
+	}
+	return &continueHandler{h: c.h.WithAttrs(attrs), err: err}
+}
+
+// WithGroup implements slog.Handler.
+func (c *continueHandler) WithGroup(name string) slog.Handler {
+	return &continueHandler{h: c.h.WithGroup(name), err: c.err}
+}
+
+// attrError 取出日志属性中的error，包括LogValuer包装的error以及group中的error
+func attrError(v slog.Value) error {
+	switch v.Kind() {
+	case slog.KindGroup:
+		for _, a := range v.Group() {
+			if err := attrError(a.Value); err != nil {
+				return err
+			}
+		}
+		return nil
+	case slog.KindAny, slog.KindLogValuer:
+	default:
+		return nil
+	}
+	switch x := v.Any().(type) {
+	case errorValuer:
+		return x.err
+	case error:
+		return x
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package xerrors
+
+import (
+	"fmt"
+	"runtime"
+
+	"github.com/pkg/errors"
+)
+
+// stack represents a stack of program counters, the same as pkg/errors does.
+type stack []uintptr
+
+// Format formats the stack of Frames according to the fmt.Formatter interface.
+// Only %+v prints anything, one frame per line.
+func (s *stack) Format(st fmt.State, verb rune) {
+	if s == nil || verb != 'v' || !st.Flag('+') {
+		return
+	}
+	for _, pc := range *s {
+		fmt.Fprintf(st, "\n%+v", errors.Frame(pc))
+	}
+}
+
+// StackTrace returns the stack as pkg/errors.StackTrace.
+func (s *stack) StackTrace() errors.StackTrace {
+	if s == nil {
+		return nil
+	}
+	f := make([]errors.Frame, len(*s))
+	for i := 0; i < len(f); i++ {
+		f[i] = errors.Frame((*s)[i])
+	}
+	return f
+}
+
+// callers 记录调用栈，skip为需要跳过的调用层数(0表示callers的调用者)
+func callers(skip int) *stack {
+	const depth = 32
+	var pcs [depth]uintptr
+	n := runtime.Callers(skip+2, pcs[:])
+	var st stack = pcs[0:n]
+	return &st
+}
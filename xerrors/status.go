@@ -0,0 +1,188 @@
+package xerrors
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/codermuhao/tools/xjson"
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+const (
+	// metadataContinueKey ErrorInfo中保存Continue标记的保留key
+	metadataContinueKey = "xerrors.continue"
+	// metadataTypedKey ErrorInfo中保存非string类型元数据的保留key，值为这些元数据的json对象
+	metadataTypedKey = "xerrors.typed"
+	// metadataRetryKey ErrorInfo中保存重试分类的保留key，重试间隔保存在errdetails.RetryInfo中
+	metadataRetryKey = "xerrors.retry"
+	// metadataReservedPrefix 保留key的前缀，用户元数据中以此开头的key统一保存在metadataTypedKey下，避免和保留key冲突
+	metadataReservedPrefix = "xerrors."
+)
+
+var (
+	grpcCodesMu sync.RWMutex
+	// grpcCodes reason或reason前缀(reason.proto中的PrefixErrorReason)到grpc code的映射
+	grpcCodes = map[string]codes.Code{
+		unknown:                 codes.Unknown,
+		"InternalError":         codes.Internal,
+		"InvalidParameter":      codes.InvalidArgument,
+		"UnknownParameter":      codes.InvalidArgument,
+		"AuthFailure":           codes.Unauthenticated,
+		"InvalidAction":         codes.FailedPrecondition,
+		"UnauthorizedOperation": codes.PermissionDenied,
+		"ResourceNotFound":      codes.NotFound,
+		"FailedOperation":       codes.FailedPrecondition,
+	}
+)
+
+// RegisterGRPCCode maps a reason, or a reason prefix such as "ResourceNotFound", to a grpc code.
+func RegisterGRPCCode(reason string, code codes.Code) {
+	grpcCodesMu.Lock()
+	defer grpcCodesMu.Unlock()
+	grpcCodes[reason] = code
+}
+
+// GRPCCode returns the grpc code of the reason.
+// The exact reason is tried first, then its dotted prefixes from the longest one,
+// codes.Unknown is returned if nothing matches.
+func GRPCCode(reason string) codes.Code {
+	grpcCodesMu.RLock()
+	defer grpcCodesMu.RUnlock()
+	code := codes.Unknown
+	matchReason(reason, func(key string) bool {
+		c, ok := grpcCodes[key]
+		if ok {
+			code = c
+		}
+		return ok
+	})
+	return code
+}
+
+// GRPCStatus returns the grpc status of the error, it makes status.FromError work on *ReasonError.
+func (e *ReasonError) GRPCStatus() *status.Status {
+	st := status.New(GRPCCode(e.Reason), e.Msg)
+	details := []proto.Message{&errdetails.ErrorInfo{
+		Reason:   e.Reason,
+		Metadata: encodeErrorInfoMetadata(e),
+	}}
+	if e.Retry != nil && e.Retry.After > 0 {
+		details = append(details, &errdetails.RetryInfo{RetryDelay: durationpb.New(e.Retry.After)})
+	}
+	ds, err := st.WithDetails(details...)
+	if err != nil {
+		return st
+	}
+	return ds
+}
+
+// ToStatus converts an error to a grpc status.
+// grpc status errors are returned as is, other errors are parsed into *ReasonError first.
+func ToStatus(err error) *status.Status {
+	if err == nil {
+		return nil
+	}
+	if se, ok := findReasonError(err); ok {
+		return se.GRPCStatus()
+	}
+	if st, ok := status.FromError(err); ok {
+		return st
+	}
+	return Parse(err).GRPCStatus()
+}
+
+// FromStatus converts a grpc status to *ReasonError.
+// The reason, continue flag and metadata are restored from errdetails.ErrorInfo,
+// a status without ErrorInfo becomes an UNKNOWN_ERROR with the status message,
+// errdetails.RetryInfo is kept in both cases.
+func FromStatus(st *status.Status) *ReasonError {
+	if st == nil {
+		return nil
+	}
+	if se := fromErrorInfo(st); se != nil {
+		return se
+	}
+	return newReasonError(0, nil, unknown, st.Message())
+}
+
+// fromErrorInfo 从status的details恢复*ReasonError，既没有ErrorInfo也没有RetryInfo时返回nil
+func fromErrorInfo(st *status.Status) *ReasonError {
+	var (
+		info  *errdetails.ErrorInfo
+		retry *errdetails.RetryInfo
+	)
+	for _, detail := range st.Details() {
+		switch d := detail.(type) {
+		case *errdetails.ErrorInfo:
+			if info == nil {
+				info = d
+			}
+		case *errdetails.RetryInfo:
+			retry = d
+		}
+	}
+	if info == nil && retry == nil {
+		return nil
+	}
+	if info == nil {
+		info = &errdetails.ErrorInfo{Reason: unknown}
+	}
+	se := newReasonError(0, nil, info.Reason, st.Message())
+	for k, v := range info.Metadata {
+		switch k {
+		case metadataContinueKey:
+			se.Continue, _ = strconv.ParseBool(v)
+		case metadataRetryKey:
+			se.Retry = &Retry{}
+			_ = se.Retry.Class.UnmarshalText([]byte(v))
+		case metadataTypedKey:
+			var typed Metadata
+			if err := xjson.Unmarshal([]byte(v), &typed); err != nil {
+				se.Metadata[k] = v
+				continue
+			}
+			for kk, vv := range typed {
+				se.Metadata[kk] = vv
+			}
+		default:
+			se.Metadata[k] = v
+		}
+	}
+	if retry != nil && retry.RetryDelay != nil {
+		if se.Retry == nil {
+			se.Retry = &Retry{Class: Retryable}
+		}
+		se.Retry.After = retry.RetryDelay.AsDuration()
+	}
+	return se
+}
+
+// encodeErrorInfoMetadata string类型元数据原样保存，其他类型以及使用保留前缀的key统一json编码后保存在metadataTypedKey下
+func encodeErrorInfoMetadata(e *ReasonError) map[string]string {
+	md := make(map[string]string, len(e.Metadata)+3)
+	typed := make(map[string]interface{})
+	for k, v := range e.Metadata {
+		if s, ok := v.(string); ok && !strings.HasPrefix(k, metadataReservedPrefix) {
+			md[k] = s
+			continue
+		}
+		typed[k] = v
+	}
+	if len(typed) > 0 {
+		if data, err := xjson.Marshal(typed); err == nil {
+			md[metadataTypedKey] = string(data)
+		}
+	}
+	if e.Continue {
+		md[metadataContinueKey] = strconv.FormatBool(e.Continue)
+	}
+	if e.Retry != nil {
+		md[metadataRetryKey] = e.Retry.Class.String()
+	}
+	return md
+}
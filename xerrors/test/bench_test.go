@@ -0,0 +1,76 @@
+package test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/codermuhao/tools/xerrors"
+	"github.com/codermuhao/tools/xjson"
+)
+
+func benchError() *xerrors.ReasonError {
+	return xerrors.NewReasonError("ResourceNotFound.UserNotFound", "用户<42>不存在").
+		WithString("user", "u&1").
+		WithInt("uid", 42).
+		WithBool("vip", true).
+		WithValue("detail", map[string]interface{}{"a": []int{1, 2}})
+}
+
+func TestReasonError_Error(t *testing.T) {
+	for _, err := range []*xerrors.ReasonError{
+		benchError(),
+		xerrors.NewReasonError("", "").WithContinue(),
+		{Reason: "nil metadata", Msg: " \x01\"\\"},
+	} {
+		want, _ := xjson.Marshal(err)
+		if got := err.Error(); got != string(want) {
+			t.Errorf("Error():\nhave %s\nwant %s", got, want)
+		}
+	}
+}
+
+func TestReasonError_Is(t *testing.T) {
+	err := benchError()
+	tests := []struct {
+		target error
+		expect bool
+	}{
+		{target: xerrors.NewReasonError(err.Reason, ""), expect: true},
+		{target: xerrors.Wrap(xerrors.NewReasonError(err.Reason, ""), "wrap"), expect: true},
+		{target: errors.New(err.Error()), expect: true},
+		{target: errors.New(`{"reason":1}`), expect: false},
+		{target: errors.New("not json"), expect: false},
+		{target: xerrors.NewReasonError("FailedOperation", ""), expect: false},
+	}
+	for _, v := range tests {
+		if got := err.Is(v.target); got != v.expect {
+			t.Errorf("Is(%v): have %v want %v", v.target, got, v.expect)
+		}
+	}
+}
+
+func BenchmarkReasonError_Error(b *testing.B) {
+	err := benchError()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = err.Error()
+	}
+}
+
+// BenchmarkReasonError_ErrorReflect 作为对比，反射编码即旧版Error()的实现
+func BenchmarkReasonError_ErrorReflect(b *testing.B) {
+	err := benchError()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		data, _ := xjson.Marshal(err)
+		_ = string(data)
+	}
+}
+
+func BenchmarkReasonError_Is(b *testing.B) {
+	err, target := benchError(), xerrors.NewReasonError("FailedOperation", "")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = errors.Is(err, target)
+	}
+}
@@ -0,0 +1,49 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/codermuhao/tools/xerrors"
+)
+
+func TestCatalog(t *testing.T) {
+	infos := []xerrors.ReasonInfo{
+		{Reason: "FailedOperation.CatalogB", Message: "b", Prefix: "FailedOperation", Source: "b.proto"},
+		{Reason: "CatalogA", Message: "a", Source: "a.proto"},
+	}
+	for _, info := range infos {
+		if err := xerrors.RegisterReason(info); err != nil {
+			t.Fatalf("RegisterReason(%v): %v", info, err)
+		}
+	}
+	if err := xerrors.RegisterReason(infos[1]); err != nil {
+		t.Errorf("re-register the same info: %v", err)
+	}
+	if err := xerrors.RegisterReason(xerrors.ReasonInfo{Reason: "CatalogA", Message: "a2", Source: "a.proto"}); err == nil {
+		t.Errorf("duplicate from the same source: have nil error")
+	}
+	if err := xerrors.RegisterReason(xerrors.ReasonInfo{Reason: "CatalogA", Source: "c.proto"}); err == nil {
+		t.Errorf("duplicate from another source: have nil error")
+	}
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Errorf("MustRegisterReason with duplicate: have no panic")
+			}
+		}()
+		xerrors.MustRegisterReason(xerrors.ReasonInfo{Reason: "CatalogA", Source: "c.proto"})
+	}()
+
+	if got, ok := xerrors.LookupReason("CatalogA"); !ok || got.Message != "a" || got.Source != "a.proto" {
+		t.Errorf("LookupReason: have %v, %v", got, ok)
+	}
+	data, err := xerrors.ExportReasons()
+	if err != nil {
+		t.Fatalf("ExportReasons: %v", err)
+	}
+	want := `[{"reason":"CatalogA","message":"a","prefix":"","source":"a.proto"},` +
+		`{"reason":"FailedOperation.CatalogB","message":"b","prefix":"FailedOperation","source":"b.proto"}]`
+	if string(data) != want {
+		t.Errorf("ExportReasons:\nhave %s\nwant %s", data, want)
+	}
+}
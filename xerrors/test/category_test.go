@@ -0,0 +1,90 @@
+package test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/codermuhao/tools/xerrors"
+)
+
+func TestCategory(t *testing.T) {
+	err := xerrors.Wrap(xerrors.NewReasonError("ResourceNotFound.User", ""), "wrap")
+	tests := []struct {
+		target error
+		expect bool
+	}{
+		{target: xerrors.ResourceNotFound, expect: true},
+		{target: xerrors.Category("ResourceNotFound.User"), expect: true},
+		{target: xerrors.Category("ResourceNotFound.Use"), expect: false},
+		{target: xerrors.Category("ResourceNot"), expect: false},
+		{target: xerrors.FailedOperation, expect: false},
+	}
+	for _, v := range tests {
+		if got := errors.Is(err, v.target); got != v.expect {
+			t.Errorf("Is(%v): have %v want %v", v.target, got, v.expect)
+		}
+	}
+	if !errors.Is(xerrors.Join(errors.New("plain"), err), xerrors.ResourceNotFound) {
+		t.Errorf("category of a joined error is lost")
+	}
+	if errors.Is(errors.New("ResourceNotFound"), xerrors.ResourceNotFound) {
+		t.Errorf("plain error should not match a category")
+	}
+}
+
+func TestIsPrefix(t *testing.T) {
+	tests := []struct {
+		input  error
+		prefix string
+		expect bool
+	}{
+		{input: xerrors.NewReasonError("FailedOperation.UserNotFound", ""), prefix: "FailedOperation", expect: true},
+		{input: xerrors.NewReasonError("FailedOperation", ""), prefix: "FailedOperation", expect: true},
+		{input: xerrors.NewReasonError("FailedOperationX", ""), prefix: "FailedOperation", expect: false},
+		{input: xerrors.NewReasonError("FailedOperation.User.Locked", ""), prefix: "FailedOperation.User",
+			expect: true},
+		{input: errors.New(xerrors.NewReasonError("FailedOperation.User", "").Error()), prefix: "FailedOperation",
+			expect: true},
+		{input: nil, prefix: "FailedOperation", expect: false},
+	}
+	for _, v := range tests {
+		if got := xerrors.IsPrefix(v.input, v.prefix); got != v.expect {
+			t.Errorf("IsPrefix(%v, %s): have %v want %v", v.input, v.prefix, got, v.expect)
+		}
+	}
+}
+
+func TestMatchPattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		reason  string
+		expect  bool
+	}{
+		{pattern: "FailedOperation.*", reason: "FailedOperation.UserNotFound", expect: true},
+		{pattern: "FailedOperation.*", reason: "FailedOperation", expect: false},
+		{pattern: "FailedOperation.*", reason: "FailedOperation.User.Locked", expect: false},
+		{pattern: "FailedOperation.**", reason: "FailedOperation", expect: true},
+		{pattern: "FailedOperation.**", reason: "FailedOperation.User.Locked", expect: true},
+		{pattern: "*.User*", reason: "ResourceNotFound.UserNotFound", expect: true},
+		{pattern: "**.Locked", reason: "FailedOperation.User.Locked", expect: true},
+		{pattern: "InvalidParameter.?d", reason: "InvalidParameter.id", expect: true},
+		{pattern: "**", reason: "Anything.At.All", expect: true},
+		{pattern: "FailedOperation.[", reason: "FailedOperation.x", expect: false},
+		{pattern: "ResourceNotFound", reason: "ResourceNotFound.User", expect: false},
+	}
+	for _, v := range tests {
+		if got := xerrors.MatchPattern(v.pattern, v.reason); got != v.expect {
+			t.Errorf("MatchPattern(%s, %s): have %v want %v", v.pattern, v.reason, got, v.expect)
+		}
+	}
+	err := xerrors.Wrap(xerrors.NewReasonError("InvalidParameter.name", ""), "bind")
+	if !xerrors.IsMatch(err, "InvalidParameter.*") || xerrors.IsMatch(err, "FailedOperation.*") ||
+		xerrors.IsMatch(nil, "**") {
+		t.Errorf("IsMatch is wrong")
+	}
+	t.Cleanup(xerrors.ResetClassifiers)
+	xerrors.RegisterClassifier(xerrors.ClassifyPattern("UnknownParameter.*", true))
+	if !xerrors.Continue(xerrors.NewReasonError("UnknownParameter.foo", "")) {
+		t.Errorf("ClassifyPattern is not applied")
+	}
+}
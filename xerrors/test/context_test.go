@@ -0,0 +1,58 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/codermuhao/tools/xerrors"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type ctxKey string
+
+func TestNewReasonErrorCtx(t *testing.T) {
+	xerrors.RegisterContextExtractor(xerrors.ContextValue("request_id", ctxKey("request_id")))
+	xerrors.RegisterContextExtractor(xerrors.ContextValue("uid", ctxKey("uid")))
+	xerrors.RegisterContextExtractor(xerrors.TraceID("trace_id"))
+
+	traceID, _ := trace.TraceIDFromHex("0af7651916cd43dd8448eb211c80319c")
+	spanID, _ := trace.SpanIDFromHex("b7ad6b7169203331")
+	ctx := trace.ContextWithSpanContext(context.Background(),
+		trace.NewSpanContext(trace.SpanContextConfig{TraceID: traceID, SpanID: spanID}))
+	ctx = context.WithValue(ctx, ctxKey("request_id"), "req-1")
+	ctx = context.WithValue(ctx, ctxKey("uid"), 42)
+
+	cause := errors.New("io")
+	tests := []struct {
+		input  *xerrors.ReasonError
+		expect *xerrors.ReasonError
+	}{
+		{
+			input: xerrors.NewReasonErrorCtx(ctx, "FailedOperation.Ctx", "failed"),
+			expect: xerrors.NewReasonError("FailedOperation.Ctx", "failed").WithString("request_id", "req-1").
+				WithInt("uid", 42).WithString("trace_id", traceID.String()),
+		},
+		{
+			input: xerrors.NewReasonErrorfCtx(ctx, "FailedOperation.Ctx", "failed %d", 1),
+			expect: xerrors.NewReasonError("FailedOperation.Ctx", "failed 1").WithString("request_id", "req-1").
+				WithInt("uid", 42).WithString("trace_id", traceID.String()),
+		},
+		{
+			input:  xerrors.WrapReasonCtx(context.WithValue(context.Background(), ctxKey("uid"), 7), cause, "Ctx", ""),
+			expect: xerrors.NewReasonError("Ctx", "").WithInt("uid", 7),
+		},
+		{
+			input:  xerrors.WrapReasonfCtx(context.Background(), cause, "Ctx", "%s", "x"),
+			expect: xerrors.NewReasonError("Ctx", "x"),
+		},
+	}
+	for _, v := range tests {
+		if !sameReasonError(v.input, v.expect) {
+			t.Errorf("have %v want %v", v.input, v.expect)
+		}
+	}
+	if !errors.Is(tests[2].input, cause) || !errors.Is(tests[3].input, cause) {
+		t.Errorf("cause is lost")
+	}
+}
@@ -0,0 +1,79 @@
+package test
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/codermuhao/tools/xerrors"
+	"github.com/codermuhao/tools/xjson"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestFromDecodeError(t *testing.T) {
+	de := &xjson.DecodeError{Errors: []*xjson.FieldError{
+		{Path: "inners[1].inner_int", Field: "inners.inner_int", Expected: "int32", Value: `"abc"`, Offset: 12, Message: `expected int32, got "abc"`},
+		{Path: "status", Field: "status", Expected: "enum test.Status", Value: `"x"`, Offset: 40, Message: `expected enum test.Status, got "x"`},
+	}}
+	se := xerrors.Parse(xerrors.Wrap(de, "bind request"))
+	if se.Reason != "InvalidParameter.inners.inner_int" || se.Msg != `expected int32, got "abc"` {
+		t.Fatalf("unexpected error %v", se)
+	}
+	if !errors.Is(se, xerrors.InvalidParameter) || xerrors.HTTPStatus(se) != http.StatusBadRequest {
+		t.Errorf("%v should be a bad request", se)
+	}
+	if v, _ := se.Metadata.GetString(xerrors.DecodeFieldKey); v != "inners[1].inner_int" {
+		t.Errorf("field: have %s", v)
+	}
+	if v, _ := se.Metadata.GetString("expected"); v != "int32" {
+		t.Errorf("expected: have %s", v)
+	}
+	if v, _ := se.Metadata.GetString("value"); v != `"abc"` {
+		t.Errorf("value: have %s", v)
+	}
+	if v, _ := se.Metadata.GetInt("offset"); v != 12 {
+		t.Errorf("offset: have %d", v)
+	}
+	if errs, _ := se.Metadata[xerrors.DecodeErrorsKey].([]interface{}); len(errs) != 2 {
+		t.Errorf("errors: have %v", se.Metadata[xerrors.DecodeErrorsKey])
+	}
+
+	err := xjson.Unmarshal([]byte(`"abc"`), new(wrapperspb.Int32Value))
+	se = xerrors.Parse(err)
+	if se.Reason != "InvalidParameter" || se.Metadata[xerrors.DecodeErrorsKey] != nil {
+		t.Errorf("unexpected error %v", se)
+	}
+	if xerrors.FromDecodeError(&xjson.DecodeError{}) != nil {
+		t.Errorf("empty DecodeError should convert to nil")
+	}
+
+	tests := []struct {
+		input  *xjson.FieldError
+		reason string
+	}{
+		{input: &xjson.FieldError{Path: "counts.attacker-controlled-key-123", Field: "counts"}, reason: "InvalidParameter.counts"},
+		{input: &xjson.FieldError{Path: "zzz_random_9f8a"}, reason: "InvalidParameter"},
+		{input: &xjson.FieldError{Path: "inner_map.5.inner_int", Field: "inner_map"}, reason: "InvalidParameter.inner_map"},
+	}
+	for _, v := range tests {
+		se := xerrors.FromDecodeError(&xjson.DecodeError{Errors: []*xjson.FieldError{v.input}})
+		if se.Reason != v.reason {
+			t.Errorf("FromDecodeError(%+v): have %s want %s", *v.input, se.Reason, v.reason)
+		}
+		if path, _ := se.Metadata.GetString(xerrors.DecodeFieldKey); path != v.input.Path {
+			t.Errorf("FromDecodeError(%+v): path have %s", *v.input, path)
+		}
+	}
+}
+
+// BFF按流读取请求体，语法错误和截断的请求体同样是400
+func TestFromDecodeError_Stream(t *testing.T) {
+	for _, input := range []string{`{"a":}`, `{"a":`} {
+		err := xjson.NewDecoder(strings.NewReader(input)).Decode(new(wrapperspb.StringValue))
+		se := xerrors.Parse(err)
+		if se.Reason != "InvalidParameter" || xerrors.HTTPStatus(err) != http.StatusBadRequest {
+			t.Errorf("decode(%s): have %v", input, se)
+		}
+	}
+}
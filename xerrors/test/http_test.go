@@ -0,0 +1,31 @@
+package test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/codermuhao/tools/xerrors"
+)
+
+func TestHTTPStatus(t *testing.T) {
+	xerrors.RegisterHTTPStatus("FailedOperation.Conflict", http.StatusConflict)
+	tests := []struct {
+		input  error
+		expect int
+	}{
+		{input: nil, expect: http.StatusOK},
+		{input: xerrors.NewReasonError("ResourceNotFound.UserNotFound", ""), expect: http.StatusNotFound},
+		{input: xerrors.NewReasonError("AuthFailure", ""), expect: http.StatusUnauthorized},
+		{input: xerrors.NewReasonError("FailedOperation.Conflict", ""), expect: http.StatusConflict},
+		{input: xerrors.NewReasonError("FailedOperation.Conflict.Version", ""), expect: http.StatusConflict},
+		{input: xerrors.NewReasonError("FailedOperation.Other", ""), expect: http.StatusBadRequest},
+		{input: xerrors.Wrap(xerrors.NewReasonError("InvalidParameter.id", ""), "wrap"), expect: http.StatusBadRequest},
+		{input: xerrors.NewReasonError("UserPasswordError", ""), expect: http.StatusInternalServerError},
+		{input: xerrors.New("plain"), expect: http.StatusInternalServerError},
+	}
+	for _, v := range tests {
+		if got, want := xerrors.HTTPStatus(v.input), v.expect; got != want {
+			t.Errorf("HTTPStatus(%v): have %d want %d", v.input, got, want)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/codermuhao/tools/xerrors"
+)
+
+func TestLocalize(t *testing.T) {
+	xerrors.MustRegisterMessage("ResourceNotFound.UserNotFound", "en", "user {{.uid}} not found")
+	xerrors.MustRegisterMessage("ResourceNotFound.UserNotFound", "zh-CN", "用户{{.uid}}不存在")
+	xerrors.MustRegisterMessage("ResourceNotFound", "en", "resource not found")
+	if err := xerrors.RegisterMessage("ResourceNotFound", "en", "{{.bad"); err == nil {
+		t.Errorf("RegisterMessage with bad template: have nil error")
+	}
+
+	err := xerrors.NewReasonError("ResourceNotFound.UserNotFound", "用户不存在").WithInt("uid", 42)
+	tests := []struct {
+		input  error
+		lang   string
+		expect string
+	}{
+		{input: err, lang: "en", expect: "user 42 not found"},
+		{input: err, lang: "en-US,en;q=0.9", expect: "user 42 not found"},
+		{input: err, lang: "zh-TW;q=0.8,zh-CN", expect: "用户42不存在"},
+		{input: err, lang: "fr", expect: "用户不存在"},
+		{input: err, lang: "", expect: "用户不存在"},
+		{input: xerrors.Wrap(xerrors.NewReasonError("ResourceNotFound.Shop", "店铺不存在"), "wrap"), lang: "en",
+			expect: "resource not found"},
+		{input: xerrors.NewReasonError("FailedOperation", "失败"), lang: "en", expect: "失败"},
+	}
+	for _, v := range tests {
+		if got := xerrors.Localize(v.input, v.lang).Msg; got != v.expect {
+			t.Errorf("Localize(%v, %q): have %s want %s", v.input, v.lang, got, v.expect)
+		}
+	}
+	if err.Msg != "用户不存在" {
+		t.Errorf("Localize modified the error: %v", err)
+	}
+}
+
+func TestLocalize_PrefixFallback(t *testing.T) {
+	xerrors.MustRegisterMessage("InvalidAction.Order", "en", "order action not allowed")
+	xerrors.MustRegisterMessage("InvalidAction", "zh", "操作不允许")
+
+	err := xerrors.NewReasonError("InvalidAction.Order", "invalid action")
+	tests := []struct {
+		lang   string
+		expect string
+	}{
+		{lang: "en", expect: "order action not allowed"},
+		{lang: "zh", expect: "操作不允许"},
+		{lang: "zh-CN,en;q=0.5", expect: "order action not allowed"},
+		{lang: "fr", expect: "invalid action"},
+	}
+	for _, v := range tests {
+		if got := xerrors.Localize(err, v.lang).Msg; got != v.expect {
+			t.Errorf("Localize(%v, %q): have %s want %s", err, v.lang, got, v.expect)
+		}
+	}
+}
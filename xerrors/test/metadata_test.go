@@ -0,0 +1,73 @@
+package test
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/codermuhao/tools/xerrors"
+	"google.golang.org/grpc/status"
+)
+
+type testDetail struct {
+	Name  string `json:"name"`
+	Count int64  `json:"count"`
+}
+
+func TestMetadata_RoundTrip(t *testing.T) {
+	detail := testDetail{Name: "foo", Count: math.MaxInt64}
+	err := xerrors.NewReasonError("FailedOperation.Quota", "quota exceeded").
+		WithString("user", "u1").
+		WithInt("limit", math.MaxInt64).
+		WithBool("vip", true).
+		WithDuration("window", 1500*time.Millisecond).
+		WithValue("detail", detail)
+
+	st, _ := status.FromError(err)
+	for name, se := range map[string]*xerrors.ReasonError{
+		"origin": err,
+		"json":   xerrors.Parse(errors.New(err.Error())),
+		"grpc":   xerrors.FromStatus(st),
+	} {
+		if !reflect.DeepEqual(se.Metadata, err.Metadata) {
+			t.Errorf("%s metadata:\nhave %#v\nwant %#v", name, se.Metadata, err.Metadata)
+		}
+		if got, ok := se.Metadata.GetString("user"); !ok || got != "u1" {
+			t.Errorf("%s GetString: have %v, %v", name, got, ok)
+		}
+		if got, ok := se.Metadata.GetInt("limit"); !ok || got != math.MaxInt64 {
+			t.Errorf("%s GetInt: have %v, %v", name, got, ok)
+		}
+		if got, ok := se.Metadata.GetBool("vip"); !ok || !got {
+			t.Errorf("%s GetBool: have %v, %v", name, got, ok)
+		}
+		if got, ok := se.Metadata.GetDuration("window"); !ok || got != 1500*time.Millisecond {
+			t.Errorf("%s GetDuration: have %v, %v", name, got, ok)
+		}
+		var got testDetail
+		if err := se.Metadata.Decode("detail", &got); err != nil || got != detail {
+			t.Errorf("%s Decode: have %v, %v", name, got, err)
+		}
+	}
+}
+
+func TestMetadata_CopyOnWrite(t *testing.T) {
+	base := xerrors.NewReasonError("FailedOperation", "failed")
+	a := base.WithString("k", "a")
+	b := base.WithMetadata(map[string]interface{}{"k": "b"}).WithContinue()
+
+	if len(base.Metadata) != 0 || base.Continue {
+		t.Errorf("base modified: %v", base)
+	}
+	if got, _ := a.Metadata.GetString("k"); got != "a" {
+		t.Errorf("a: have %s want a", got)
+	}
+	if got, _ := b.Metadata.GetString("k"); got != "b" || !b.Continue {
+		t.Errorf("b: have %s, %v", got, b.Continue)
+	}
+	if !errors.Is(a, base) || !errors.Is(b, base) {
+		t.Errorf("copies should keep the reason")
+	}
+}
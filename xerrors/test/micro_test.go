@@ -0,0 +1,86 @@
+package test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/codermuhao/tools/xerrors"
+
+	microerrors "github.com/asim/go-micro/v3/errors"
+)
+
+func TestMicroError_RoundTrip(t *testing.T) {
+	tests := []struct {
+		input *xerrors.ReasonError
+		opts  []xerrors.MicroOption
+		id    string
+		code  int32
+	}{
+		{
+			input: xerrors.NewReasonError("ResourceNotFound.UserNotFound", "user not found").WithInt("uid", 10),
+			opts:  []xerrors.MicroOption{xerrors.WithMicroID("go.micro.srv.user")},
+			id:    "go.micro.srv.user",
+			code:  http.StatusNotFound,
+		},
+		{
+			input: xerrors.NewReasonError("FailedOperation.Locked", "locked").WithContinue(),
+			opts: []xerrors.MicroOption{xerrors.WithMicroCode(func(reason string) int32 {
+				return http.StatusConflict
+			})},
+			code: http.StatusConflict,
+		},
+	}
+	for _, v := range tests {
+		me := xerrors.ToMicroError(v.input, v.opts...)
+		if me.Id != v.id || me.Code != v.code || me.Status != http.StatusText(int(v.code)) {
+			t.Errorf("ToMicroError(%v): have %v", v.input, me)
+		}
+		parsed := microerrors.Parse(me.Error())
+		if got := xerrors.FromMicroError(parsed); !sameReasonError(got, v.input) {
+			t.Errorf("FromMicroError(%v):\nhave %v\nwant %v", parsed, got, v.input)
+		}
+		if got := xerrors.Parse(xerrors.Wrap(parsed, "call")); !sameReasonError(got, v.input) {
+			t.Errorf("Parse(%v):\nhave %v\nwant %v", parsed, got, v.input)
+		}
+	}
+}
+
+func TestFromMicroError_Plain(t *testing.T) {
+	tests := []struct {
+		input  *microerrors.Error
+		reason string
+		status int
+	}{
+		{input: microerrors.InternalServerError("go.micro.srv.user", "db down").(*microerrors.Error), reason: "InternalError", status: http.StatusInternalServerError},
+		{input: microerrors.NotFound("go.micro.srv.user", "no user").(*microerrors.Error), reason: "ResourceNotFound", status: http.StatusNotFound},
+		{input: microerrors.BadRequest("go.micro.srv.user", "bad id").(*microerrors.Error), reason: "InvalidParameter", status: http.StatusBadRequest},
+		{input: microerrors.New("", "teapot", http.StatusTeapot).(*microerrors.Error), reason: "UNKNOWN_ERROR", status: xerrors.DefaultHTTPStatus},
+	}
+	for _, v := range tests {
+		got := xerrors.FromMicroError(v.input)
+		id, _ := got.Metadata.GetString(xerrors.MicroIDMetadataKey)
+		if got.Reason != v.reason || got.Msg != v.input.Detail || id != v.input.Id {
+			t.Errorf("FromMicroError(%v): have %v", v.input, got)
+		}
+		if xerrors.HTTPStatus(got) != v.status {
+			t.Errorf("HTTPStatus(%v): have %d want %d", got, xerrors.HTTPStatus(got), v.status)
+		}
+		if xerrors.ToMicroError(v.input) != v.input {
+			t.Errorf("ToMicroError should keep go-micro errors")
+		}
+	}
+}
+
+func TestToMicroError_WrapReason(t *testing.T) {
+	inner := microerrors.NotFound("go.micro.srv.user", "user not found").(*microerrors.Error)
+	me := xerrors.ToMicroError(xerrors.WrapReason(inner, "FailedOperation.Outer", "outer"))
+	if me == inner || me.Code != http.StatusBadRequest {
+		t.Fatalf("ToMicroError should convert the outer reason, have %v", me)
+	}
+	if got := xerrors.FromMicroError(me); got.Reason != "FailedOperation.Outer" || got.Msg != "outer" {
+		t.Errorf("FromMicroError(%v): have %v", me, got)
+	}
+	if got := xerrors.ToMicroError(xerrors.Wrap(inner, "call")); got != inner {
+		t.Errorf("ToMicroError should keep a wrapped go-micro error, have %v", got)
+	}
+}
@@ -0,0 +1,57 @@
+package test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/codermuhao/tools/xerrors"
+)
+
+func TestMultiError(t *testing.T) {
+	notFound := xerrors.NewReasonError("ResourceNotFound.UserNotFound", "user not found")
+	denied := xerrors.NewReasonError("UnauthorizedOperation.Denied", "denied").WithContinue()
+	me := xerrors.NewMultiError().
+		Add("1", notFound.WithInt("uid", 1)).
+		Add("2", nil).
+		Add("3", xerrors.Wrap(denied, "wrap"))
+
+	if got, want := me.Len(), 2; got != want {
+		t.Fatalf("Len: have %d want %d", got, want)
+	}
+	want := `[{"key":"1","error":{"msg":"user not found","reason":"ResourceNotFound.UserNotFound","continue":false,"metadata":{"uid":1}}},` +
+		`{"key":"3","error":{"msg":"denied","reason":"UnauthorizedOperation.Denied","continue":true,"metadata":{}}}]`
+	if got := me.Error(); got != want {
+		t.Errorf("Error:\nhave %s\nwant %s", got, want)
+	}
+
+	var err error = xerrors.Wrap(me, "batch")
+	if !errors.Is(err, notFound) || !errors.Is(err, denied) {
+		t.Errorf("errors.Is should match any contained reason")
+	}
+	if errors.Is(err, xerrors.NewReasonError("FailedOperation", "")) {
+		t.Errorf("errors.Is should not match other reasons")
+	}
+	se := new(xerrors.ReasonError)
+	if !errors.As(err, &se) || se.Reason != notFound.Reason {
+		t.Errorf("errors.As: have %v", se)
+	}
+
+	parsed := xerrors.ParseMulti(errors.New(me.Error()))
+	if parsed.Len() != me.Len() {
+		t.Fatalf("ParseMulti: have %v", parsed)
+	}
+	for i, item := range parsed.Items {
+		if item.Key != me.Items[i].Key || !sameReasonError(item.Error, me.Items[i].Error) {
+			t.Errorf("ParseMulti item %d: have %v want %v", i, item, me.Items[i])
+		}
+	}
+	if got := xerrors.Parse(errors.New(me.Error())); !sameReasonError(got, me.Items[0].Error) {
+		t.Errorf("Parse: have %v want %v", got, me.Items[0].Error)
+	}
+	if got := parsed.Get("3"); got == nil || !got.Continue {
+		t.Errorf("Get: have %v", got)
+	}
+	if xerrors.NewMultiError().ErrorOrNil() != nil {
+		t.Errorf("ErrorOrNil of empty MultiError: have non-nil")
+	}
+}
@@ -0,0 +1,66 @@
+package test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/codermuhao/tools/xerrors"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+func TestCollector(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	c := xerrors.NewCollector("test")
+	reg.MustRegister(c)
+	xerrors.RegisterObserver(c.Observe)
+
+	xerrors.NewReasonError("Metrics.NotFound", "")
+	xerrors.NewReasonErrorf("Metrics.NotFound", "%d", 1)
+	xerrors.WrapReason(errors.New("io"), "Metrics.Busy", "").WithContinue()
+	xerrors.ObserveReturn(xerrors.NewReasonError("Metrics.Busy", "").WithContinue())
+	xerrors.ObserveReturn(nil)
+	xerrors.Parse(errors.New("not observed"))
+
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(families) != 1 || families[0].GetName() != "test_xerrors_reason_occurrences_total" {
+		t.Fatalf("unexpected metric families: %v", families)
+	}
+	got := make(map[[4]string]float64)
+	for _, m := range families[0].GetMetric() {
+		got[metricLabels(m)] = m.GetCounter().GetValue()
+	}
+	expect := map[[4]string]float64{
+		{"Metrics.NotFound", "Metrics", "", "new"}:    2,
+		{"Metrics.Busy", "Metrics", "", "new"}:        2,
+		{"Metrics.Busy", "Metrics", "true", "return"}: 1,
+	}
+	for k, v := range expect {
+		if got[k] != v {
+			t.Errorf("%v: have %v want %v", k, got[k], v)
+		}
+	}
+	if len(got) != len(expect) {
+		t.Errorf("have %v want %v", got, expect)
+	}
+}
+
+func metricLabels(m *dto.Metric) [4]string {
+	var labels [4]string
+	for _, l := range m.GetLabel() {
+		switch l.GetName() {
+		case "reason":
+			labels[0] = l.GetValue()
+		case "prefix":
+			labels[1] = l.GetValue()
+		case "continue":
+			labels[2] = l.GetValue()
+		case "stage":
+			labels[3] = l.GetValue()
+		}
+	}
+	return labels
+}
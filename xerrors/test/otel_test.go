@@ -0,0 +1,135 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/codermuhao/tools/xerrors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestRecordOnSpan(t *testing.T) {
+	xerrors.RegisterMetadataKey("otel_token", xerrors.Sensitive)
+	exporter := tracetest.NewInMemoryExporter()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)).Tracer("test")
+
+	tests := []struct {
+		input  error
+		status codes.Code
+		attrs  map[attribute.Key]attribute.Value
+		events int
+	}{
+		{
+			input: xerrors.NewReasonError("FailedOperation.Span", "failed").
+				WithString("uid", "1").WithInt("count", 2).WithString("otel_token", "secret"),
+			status: codes.Error,
+			attrs: map[attribute.Key]attribute.Value{
+				"xerrors.reason":              attribute.StringValue("FailedOperation.Span"),
+				"xerrors.prefix":              attribute.StringValue("FailedOperation"),
+				"xerrors.continue":            attribute.BoolValue(false),
+				"xerrors.metadata.uid":        attribute.StringValue("1"),
+				"xerrors.metadata.count":      attribute.Int64Value(2),
+				"xerrors.metadata.otel_token": attribute.StringValue(xerrors.RedactedValue),
+			},
+			events: 1,
+		},
+		{
+			input:  xerrors.NewReasonError("ResourceNotFound", "").WithContinue(),
+			status: codes.Unset,
+			attrs: map[attribute.Key]attribute.Value{
+				"xerrors.reason":   attribute.StringValue("ResourceNotFound"),
+				"xerrors.prefix":   attribute.StringValue("ResourceNotFound"),
+				"xerrors.continue": attribute.BoolValue(true),
+			},
+		},
+	}
+	for _, v := range tests {
+		exporter.Reset()
+		ctx, span := tracer.Start(context.Background(), "handler")
+		xerrors.RecordOnSpan(ctx, v.input)
+		xerrors.RecordOnSpan(ctx, nil)
+		span.End()
+
+		spans := exporter.GetSpans()
+		if len(spans) != 1 {
+			t.Fatalf("have %d spans", len(spans))
+		}
+		if spans[0].Status.Code != v.status {
+			t.Errorf("%v: status have %v want %v", v.input, spans[0].Status.Code, v.status)
+		}
+		if len(spans[0].Events) != v.events {
+			t.Errorf("%v: events have %d want %d", v.input, len(spans[0].Events), v.events)
+		}
+		for _, event := range spans[0].Events {
+			for _, kv := range event.Attributes {
+				if strings.Contains(kv.Value.Emit(), "secret") {
+					t.Errorf("%v: event attribute %s leaks sensitive metadata: %s", v.input, kv.Key, kv.Value.Emit())
+				}
+				if kv.Key == "exception.message" && !strings.Contains(kv.Value.Emit(), `"otel_token":"`+xerrors.RedactedValue+`"`) {
+					t.Errorf("%v: exception.message have %s", v.input, kv.Value.Emit())
+				}
+			}
+		}
+		got := make(map[attribute.Key]attribute.Value)
+		for _, kv := range spans[0].Attributes {
+			got[kv.Key] = kv.Value
+		}
+		if len(got) != len(v.attrs) {
+			t.Errorf("%v: attributes have %v want %v", v.input, got, v.attrs)
+		}
+		for k, want := range v.attrs {
+			if got[k] != want {
+				t.Errorf("%v: %s have %v want %v", v.input, k, got[k].Emit(), want.Emit())
+			}
+		}
+	}
+	// 没有span时不做任何事
+	xerrors.RecordOnSpan(context.Background(), xerrors.NewReasonError("FailedOperation.Span", ""))
+}
+
+func TestRecordOnSpan_Wrapped(t *testing.T) {
+	xerrors.RegisterMetadataKey("otel_token", xerrors.Sensitive)
+	exporter := tracetest.NewInMemoryExporter()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)).Tracer("test")
+
+	tests := []struct {
+		input   error
+		typ     string
+		message string
+	}{
+		{
+			input: xerrors.Wrap(xerrors.NewReasonError("FailedOperation.Span", "failed").
+				WithString("otel_token", "secret"), "handler"),
+			typ:     "*errors.withStack",
+			message: `handler: {"msg":"failed","reason":"FailedOperation.Span","continue":false,"metadata":{"otel_token":"******"}}`,
+		},
+		{
+			input:   errors.New("plain"),
+			typ:     "*errors.errorString",
+			message: "plain",
+		},
+	}
+	for _, v := range tests {
+		exporter.Reset()
+		ctx, span := tracer.Start(context.Background(), "handler")
+		xerrors.RecordOnSpan(ctx, v.input)
+		span.End()
+
+		events := exporter.GetSpans()[0].Events
+		if len(events) != 1 {
+			t.Fatalf("%v: have %d events", v.input, len(events))
+		}
+		got := make(map[attribute.Key]string)
+		for _, kv := range events[0].Attributes {
+			got[kv.Key] = kv.Value.Emit()
+		}
+		if got["exception.type"] != v.typ || got["exception.message"] != v.message {
+			t.Errorf("%v: exception have %v", v.input, got)
+		}
+	}
+}
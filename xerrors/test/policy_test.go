@@ -0,0 +1,53 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/codermuhao/tools/xerrors"
+)
+
+func TestContinue(t *testing.T) {
+	t.Cleanup(xerrors.ResetClassifiers)
+	xerrors.RegisterClassifier(xerrors.ClassifyPrefix("InvalidParameter", true))
+	xerrors.RegisterClassifier(xerrors.ClassifyMetadata("skip_alert"))
+	xerrors.RegisterClassifier(xerrors.ClassifyIs(context.Canceled, true))
+	xerrors.RegisterClassifier(xerrors.ClassifyType((*net.OpError)(nil), false))
+
+	opErr := &net.OpError{Op: "dial", Err: errors.New("refused")}
+	tests := []struct {
+		input  error
+		expect bool
+		level  xerrors.Level
+	}{
+		{input: nil, expect: true, level: xerrors.LevelDebug},
+		{input: xerrors.NewReasonError("FailedOperation.Policy", ""), expect: false, level: xerrors.LevelError},
+		{input: xerrors.NewReasonError("FailedOperation.Policy", "").WithContinue(), expect: true,
+			level: xerrors.LevelInfo},
+		{input: xerrors.NewReasonError("InvalidParameter.id", ""), expect: true, level: xerrors.LevelInfo},
+		{input: xerrors.NewReasonError("InvalidParameterX", ""), expect: false, level: xerrors.LevelError},
+		{input: xerrors.NewReasonError("FailedOperation.Policy", "").WithBool("skip_alert", true), expect: true,
+			level: xerrors.LevelInfo},
+		{input: xerrors.NewReasonError("FailedOperation.Policy", "").WithContinue().WithBool("skip_alert", false),
+			expect: false, level: xerrors.LevelError},
+		{input: xerrors.Wrap(context.Canceled, "request"), expect: true, level: xerrors.LevelInfo},
+		{input: xerrors.WrapReason(opErr, "InternalError.Dial", "").WithContinue(), expect: false,
+			level: xerrors.LevelError},
+		{input: xerrors.Join(xerrors.NewReasonError("FailedOperation.Policy", "").WithContinue(), opErr),
+			expect: false, level: xerrors.LevelError},
+		{input: errors.New("plain"), expect: false, level: xerrors.LevelError},
+	}
+	for _, v := range tests {
+		if got := xerrors.Continue(v.input); got != v.expect {
+			t.Errorf("Continue(%v): have %v want %v", v.input, got, v.expect)
+		}
+		if got := xerrors.ShouldAlert(v.input); got != (v.input != nil && !v.expect) {
+			t.Errorf("ShouldAlert(%v): have %v", v.input, got)
+		}
+		if got := xerrors.LogLevel(v.input); got != v.level {
+			t.Errorf("LogLevel(%v): have %v want %v", v.input, got, v.level)
+		}
+	}
+}
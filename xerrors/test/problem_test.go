@@ -0,0 +1,93 @@
+package test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/codermuhao/tools/xerrors"
+)
+
+func TestProblem(t *testing.T) {
+	xerrors.RegisterMetadataKey("problem_sql", xerrors.Internal)
+	err := xerrors.NewReasonError("ResourceNotFound.UserNotFound", "user not found").
+		WithInt("uid", 42).WithString("problem_sql", "select 1").WithRetryAfter(time.Second).WithContinue()
+
+	p := xerrors.ToProblem(xerrors.Wrap(err, "wrap"), xerrors.WithProblemInstance("/iam/get"))
+	data, _ := p.MarshalJSON()
+	want := `{"continue":true,"detail":"user not found","instance":"/iam/get","metadata":{"uid":42},` +
+		`"reason":"ResourceNotFound.UserNotFound","retry":{"class":"rate_limited","after":1000000000},` +
+		`"status":404,"title":"Not Found","type":"about:blank"}`
+	if string(data) != want {
+		t.Errorf("MarshalJSON:\nhave %s\nwant %s", data, want)
+	}
+	got, perr := xerrors.ParseProblem(data)
+	if perr != nil {
+		t.Fatalf("ParseProblem: %v", perr)
+	}
+	if want := xerrors.Redact(err, xerrors.RenderPublic); !sameReasonError(got, want) ||
+		got.Retry == nil || *got.Retry != *want.Retry {
+		t.Errorf("ParseProblem:\nhave %v\nwant %v", got, want)
+	}
+
+	typed := xerrors.WithProblemTypeBase("https://errors.example.com/")
+	p = xerrors.ToProblem(err, typed, xerrors.WithProblemMode(xerrors.RenderInternal))
+	if p.Type != "https://errors.example.com/ResourceNotFound.UserNotFound" || p.Title != err.Reason {
+		t.Errorf("type and title: have %s, %s", p.Type, p.Title)
+	}
+	delete(p.Extensions, "reason")
+	data, _ = p.MarshalJSON()
+	if got, _ := xerrors.ParseProblem(data, typed); !sameReasonError(got, err) {
+		t.Errorf("reason from type:\nhave %v\nwant %v", got, err)
+	}
+}
+
+func TestMarshalHTTP(t *testing.T) {
+	err := xerrors.NewReasonError("InvalidParameter.id", "bad id")
+	tests := []struct {
+		accept      string
+		contentType string
+	}{
+		{accept: "", contentType: xerrors.JSONContentType},
+		{accept: "application/json", contentType: xerrors.JSONContentType},
+		{accept: "application/json, application/problem+json;q=0.9", contentType: xerrors.ProblemContentType},
+		{accept: "application/problem+json;q=0", contentType: xerrors.JSONContentType},
+	}
+	for _, v := range tests {
+		status, contentType, body := xerrors.MarshalHTTP(err, v.accept)
+		if status != http.StatusBadRequest || contentType != v.contentType || len(body) == 0 {
+			t.Errorf("MarshalHTTP(%q): have %d, %s, %s", v.accept, status, contentType, body)
+		}
+	}
+}
+
+func TestMarshalHTTP_MultiError(t *testing.T) {
+	xerrors.RegisterMetadataKey("token", xerrors.Sensitive)
+	xerrors.MustRegisterMessage("UnknownParameter.MultiB", "en", "b not found")
+	me := xerrors.NewMultiError().
+		Add("a", xerrors.NewReasonError("UnknownParameter.MultiA", "a").WithString("token", "abc")).
+		Add("b", xerrors.NewReasonError("UnknownParameter.MultiB", "b"))
+	err := xerrors.Wrap(me, "batch")
+	lang := xerrors.WithProblemLanguage("en")
+
+	status, contentType, body := xerrors.MarshalHTTP(err, "application/json", lang)
+	want := `[{"key":"a","error":{"msg":"a","reason":"UnknownParameter.MultiA","continue":false,"metadata":{"token":"******"}}},` +
+		`{"key":"b","error":{"msg":"b not found","reason":"UnknownParameter.MultiB","continue":false,"metadata":{}}}]`
+	if status != http.StatusBadRequest || contentType != xerrors.JSONContentType || string(body) != want {
+		t.Errorf("MarshalHTTP json: have %d, %s\nhave %s\nwant %s", status, contentType, body, want)
+	}
+
+	status, contentType, body = xerrors.MarshalHTTP(err, "application/problem+json", lang)
+	want = `{"continue":false,"detail":"a","errors":[` +
+		`{"key":"a","error":{"msg":"a","reason":"UnknownParameter.MultiA","continue":false,"metadata":{"token":"******"}}},` +
+		`{"key":"b","error":{"msg":"b not found","reason":"UnknownParameter.MultiB","continue":false,"metadata":{}}}],` +
+		`"metadata":{"token":"******"},"reason":"UnknownParameter.MultiA","status":400,"title":"Bad Request","type":"about:blank"}`
+	if status != http.StatusBadRequest || contentType != xerrors.ProblemContentType || string(body) != want {
+		t.Errorf("MarshalHTTP problem: have %d, %s\nhave %s\nwant %s", status, contentType, body, want)
+	}
+
+	wrapped := xerrors.WrapReason(me, "FailedOperation.Batch", "batch failed")
+	if _, _, body := xerrors.MarshalHTTP(wrapped, ""); string(body) != xerrors.Redact(wrapped, xerrors.RenderPublic).Error() {
+		t.Errorf("an outer ReasonError should be rendered alone, have %s", body)
+	}
+}
@@ -0,0 +1,65 @@
+package test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/codermuhao/tools/xerrors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestJoin(t *testing.T) {
+	notFound := xerrors.NewReasonError("ResourceNotFound.User", "user not found")
+	denied := xerrors.NewReasonError("UnauthorizedOperation.User", "denied")
+	plain := errors.New("plain")
+
+	if xerrors.Join(nil, nil) != nil {
+		t.Errorf("Join of nil errors should be nil")
+	}
+	tests := []struct {
+		input  error
+		expect string
+	}{
+		{input: xerrors.Join(plain, notFound, denied), expect: "ResourceNotFound.User"},
+		{input: xerrors.Join(xerrors.Wrap(xerrors.Wrap(notFound, "deep"), "deeper"), denied),
+			expect: "UnauthorizedOperation.User"},
+		{input: fmt.Errorf("multi: %w, %w", plain, denied), expect: "UnauthorizedOperation.User"},
+		{input: xerrors.Wrap(xerrors.Join(plain, xerrors.WrapReason(notFound, "InternalError.Outer", "")), "wrap"),
+			expect: "InternalError.Outer"},
+		{input: xerrors.Join(plain, status.Error(codes.NotFound, "grpc")), expect: "UNKNOWN_ERROR"},
+		{input: xerrors.Join(plain, xerrors.NewMultiError().Add("1", denied).Add("2", notFound)),
+			expect: "UnauthorizedOperation.User"},
+	}
+	for _, v := range tests {
+		if got := xerrors.Parse(v.input).Reason; got != v.expect {
+			t.Errorf("Parse(%v): have %s want %s", v.input, got, v.expect)
+		}
+	}
+
+	joined := xerrors.Join(plain, xerrors.Wrap(notFound, "wrap"), denied)
+	for _, target := range []error{plain, notFound, denied, xerrors.NewReasonError("ResourceNotFound.User", "")} {
+		if !xerrors.Is(joined, target) {
+			t.Errorf("Is(%v) should be true", target)
+		}
+	}
+	if xerrors.Is(joined, xerrors.NewReasonError("InternalError", "")) {
+		t.Errorf("Is(InternalError) should be false")
+	}
+	if !xerrors.NewReasonError("UnauthorizedOperation.User", "").Is(fmt.Errorf("%w, %w", plain, denied)) {
+		t.Errorf("ReasonError.Is should match a joined error")
+	}
+	if !xerrors.IsRetryable(xerrors.Join(plain, xerrors.NewReasonError("Busy", "").WithRetry(xerrors.Retryable))) {
+		t.Errorf("retry class of a joined error is lost")
+	}
+	if got := len(xerrors.UnwrapAll(joined)); got != 3 {
+		t.Errorf("UnwrapAll: have %d want 3", got)
+	}
+	if got := xerrors.UnwrapAll(xerrors.Wrap(plain, "wrap")); len(got) != 1 {
+		t.Errorf("UnwrapAll: have %v", got)
+	}
+	if xerrors.UnwrapAll(plain) != nil {
+		t.Errorf("UnwrapAll of a plain error should be nil")
+	}
+}
@@ -0,0 +1,97 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/codermuhao/tools/xerrors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+func panicking(v interface{}) (err error) {
+	defer xerrors.Recover(&err)
+	doPanic(v)
+	return nil
+}
+
+func doPanic(v interface{}) {
+	panic(v)
+}
+
+func TestRecover(t *testing.T) {
+	cause := errors.New("boom")
+	tests := []struct {
+		input interface{}
+		value string
+		cause error
+	}{
+		{input: "oops", value: "oops"},
+		{input: cause, value: "boom", cause: cause},
+		{input: 42, value: "42"},
+	}
+	for _, v := range tests {
+		err := panicking(v.input)
+		se := xerrors.Parse(err)
+		if se.Reason != xerrors.PanicReason || !errors.Is(err, xerrors.InternalError) {
+			t.Errorf("reason: have %s", se.Reason)
+		}
+		if got, _ := se.Metadata.GetString(xerrors.PanicMetadataKey); got != v.value {
+			t.Errorf("panic value: have %s want %s", got, v.value)
+		}
+		if v.cause != nil && !errors.Is(err, v.cause) {
+			t.Errorf("cause is lost")
+		}
+		if st := se.StackTrace(); len(st) == 0 || !strings.HasSuffix(fmt.Sprintf("%n", st[0]), "doPanic") {
+			t.Errorf("stack should start at the panicking function: %+v", st)
+		}
+		if _, ok := xerrors.Redact(err, xerrors.RenderPublic).Metadata[xerrors.PanicMetadataKey]; ok {
+			t.Errorf("panic value should not be rendered publicly")
+		}
+	}
+	if err := panicking(nil); err != nil {
+		// panic(nil)在Go 1.21+中为*runtime.PanicNilError
+		if !strings.Contains(err.Error(), xerrors.PanicReason) {
+			t.Errorf("panic(nil): have %v", err)
+		}
+	}
+
+	var got *xerrors.ReasonError
+	func() {
+		defer xerrors.RecoverWith(func(se *xerrors.ReasonError) { got = se })
+		doPanic("with")
+	}()
+	if got == nil || got.Reason != xerrors.PanicReason {
+		t.Errorf("RecoverWith: have %v", got)
+	}
+}
+
+func TestServerRecovery(t *testing.T) {
+	_, err := xerrors.UnaryServerRecovery()(context.Background(), nil, &grpc.UnaryServerInfo{},
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			doPanic("unary")
+			return nil, nil
+		})
+	st, _ := status.FromError(err)
+	if se := xerrors.FromStatus(st); se.Reason != xerrors.PanicReason {
+		t.Errorf("unary: have %v", se)
+	}
+	err = xerrors.StreamServerRecovery()(nil, nil, &grpc.StreamServerInfo{},
+		func(srv interface{}, stream grpc.ServerStream) error {
+			doPanic("stream")
+			return nil
+		})
+	if se := xerrors.Parse(err); se.Reason != xerrors.PanicReason {
+		t.Errorf("stream: have %v", se)
+	}
+	rsp, err := xerrors.UnaryServerRecovery()(context.Background(), "req", &grpc.UnaryServerInfo{},
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			return req, nil
+		})
+	if rsp != "req" || err != nil {
+		t.Errorf("no panic: have %v %v", rsp, err)
+	}
+}
@@ -0,0 +1,55 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/codermuhao/tools/xerrors"
+	"google.golang.org/grpc/status"
+)
+
+func TestRedact(t *testing.T) {
+	xerrors.RegisterMetadataKey("sql", xerrors.Internal)
+	xerrors.RegisterMetadataKey("token", xerrors.Sensitive)
+	err := xerrors.NewReasonError("InternalError.Redact", "query failed").
+		WithString("sql", "select * from user").
+		WithString("token", "abc").
+		WithInt("uid", 1)
+
+	want := `{"msg":"query failed","reason":"InternalError.Redact","continue":false,"metadata":{"token":"******","uid":1}}`
+	if got := err.Render(xerrors.RenderPublic); got != want {
+		t.Errorf("RenderPublic:\nhave %s\nwant %s", got, want)
+	}
+	if got := xerrors.Redact(xerrors.Wrap(err, "wrap"), xerrors.RenderPublic).Error(); got != want {
+		t.Errorf("Redact:\nhave %s\nwant %s", got, want)
+	}
+	if got, want := err.Render(xerrors.RenderInternal), err.Error(); got != want {
+		t.Errorf("RenderInternal:\nhave %s\nwant %s", got, want)
+	}
+	st, _ := status.FromError(err)
+	if got := xerrors.FromStatus(st); !sameReasonError(got, err) {
+		t.Errorf("grpc keeps internal metadata: have %v want %v", got, err)
+	}
+	if _, ok := err.Metadata["sql"]; !ok {
+		t.Errorf("Redact modified the error: %v", err)
+	}
+}
+
+func TestMultiError_Redact(t *testing.T) {
+	xerrors.RegisterMetadataKey("sql", xerrors.Internal)
+	xerrors.RegisterMetadataKey("token", xerrors.Sensitive)
+	me := xerrors.NewMultiError().
+		Add("a", xerrors.NewReasonError("ResourceNotFound.A", "a").WithString("sql", "select 1")).
+		Add("b", xerrors.NewReasonError("ResourceNotFound.B", "b").WithString("token", "abc"))
+
+	want := `[{"key":"a","error":{"msg":"a","reason":"ResourceNotFound.A","continue":false,"metadata":{}}},` +
+		`{"key":"b","error":{"msg":"b","reason":"ResourceNotFound.B","continue":false,"metadata":{"token":"******"}}}]`
+	if got := me.Render(xerrors.RenderPublic); got != want {
+		t.Errorf("RenderPublic:\nhave %s\nwant %s", got, want)
+	}
+	if got, want := me.Render(xerrors.RenderInternal), me.Error(); got != want {
+		t.Errorf("RenderInternal:\nhave %s\nwant %s", got, want)
+	}
+	if _, ok := me.Get("a").Metadata["sql"]; !ok {
+		t.Errorf("Redact modified the error: %v", me)
+	}
+}
@@ -0,0 +1,96 @@
+package test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/codermuhao/tools/xerrors"
+	"github.com/codermuhao/tools/xjson"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func TestRetry(t *testing.T) {
+	base := xerrors.NewReasonError("FailedOperation.Busy", "busy")
+	tests := []struct {
+		input      error
+		retryable  bool
+		temporary  bool
+		permanent  bool
+		retryAfter time.Duration
+	}{
+		{input: base},
+		{input: base.WithRetry(xerrors.Retryable), retryable: true},
+		{input: base.WithRetry(xerrors.Temporary), retryable: true, temporary: true},
+		{input: base.WithRetry(xerrors.Permanent), permanent: true},
+		{input: base.WithRetryAfter(2 * time.Second), retryable: true, temporary: true, retryAfter: 2 * time.Second},
+		{input: xerrors.Wrap(base.WithRetryAfter(time.Second), "wrap"), retryable: true, temporary: true,
+			retryAfter: time.Second},
+		{input: base.WithRetryPolicy(xerrors.Temporary, time.Second), retryable: true, temporary: true,
+			retryAfter: time.Second},
+		{input: base.WithRetryPolicy(xerrors.Permanent, time.Second), permanent: true, retryAfter: time.Second},
+		{input: errors.New("plain")},
+	}
+	for _, v := range tests {
+		st, _ := status.FromError(xerrors.Parse(v.input))
+		for name, err := range map[string]error{
+			"origin": v.input,
+			"json":   errors.New(xerrors.Parse(v.input).Error()),
+			"grpc":   st.Err(),
+		} {
+			if got := xerrors.IsRetryable(err); got != v.retryable {
+				t.Errorf("%s IsRetryable(%v): have %v want %v", name, err, got, v.retryable)
+			}
+			if got := xerrors.IsTemporary(err); got != v.temporary {
+				t.Errorf("%s IsTemporary(%v): have %v want %v", name, err, got, v.temporary)
+			}
+			if got := xerrors.IsPermanent(err); got != v.permanent {
+				t.Errorf("%s IsPermanent(%v): have %v want %v", name, err, got, v.permanent)
+			}
+			if got, ok := xerrors.RetryAfter(err); got != v.retryAfter || ok != (v.retryAfter > 0) {
+				t.Errorf("%s RetryAfter(%v): have %v, %v want %v", name, err, got, ok, v.retryAfter)
+			}
+		}
+	}
+}
+
+func TestRetry_JSON(t *testing.T) {
+	err := xerrors.NewReasonError("FailedOperation.Busy", "busy").WithRetryAfter(time.Second)
+	want := `{"msg":"busy","reason":"FailedOperation.Busy","continue":false,"metadata":{},` +
+		`"retry":{"class":"rate_limited","after":1000000000}}`
+	if got := err.Error(); got != want {
+		t.Errorf("Error():\nhave %s\nwant %s", got, want)
+	}
+	if data, _ := xjson.Marshal(err); string(data) != want {
+		t.Errorf("xjson.Marshal:\nhave %s\nwant %s", data, want)
+	}
+}
+
+func TestRetry_ForeignRetryInfo(t *testing.T) {
+	st, _ := status.New(codes.ResourceExhausted, "slow down").WithDetails(
+		&errdetails.ErrorInfo{Reason: "RateLimitExceeded"},
+		&errdetails.RetryInfo{RetryDelay: durationpb.New(3 * time.Second)},
+	)
+	if d, ok := xerrors.RetryAfter(st.Err()); !ok || d != 3*time.Second {
+		t.Errorf("RetryAfter: have %v, %v", d, ok)
+	}
+	if !xerrors.IsRetryable(st.Err()) {
+		t.Errorf("IsRetryable: have false")
+	}
+}
+
+func TestRetry_RetryInfoOnly(t *testing.T) {
+	st, _ := status.New(codes.Unavailable, "down").WithDetails(
+		&errdetails.RetryInfo{RetryDelay: durationpb.New(2 * time.Second)},
+	)
+	se := xerrors.FromStatus(st)
+	if se.Reason != "UNKNOWN_ERROR" || se.Msg != "down" {
+		t.Errorf("FromStatus: have %v", se)
+	}
+	if d, ok := xerrors.RetryAfter(st.Err()); !ok || d != 2*time.Second {
+		t.Errorf("RetryAfter: have %v, %v", d, ok)
+	}
+}
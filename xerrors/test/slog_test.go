@@ -0,0 +1,148 @@
+//go:build go1.21
+
+package test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"testing"
+
+	"github.com/codermuhao/tools/xerrors"
+)
+
+func TestReasonError_LogValue(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	err := xerrors.WrapReason(xerrors.New("dial timeout"), "InternalError.Slog", "dial db").
+		WithInt("port", 3306).WithContinue()
+	logger.Error("call failed", "err", err, xerrors.Attr(xerrors.Wrap(err, "handler")))
+
+	var got struct {
+		Err struct {
+			Reason   string                 `json:"reason"`
+			Msg      string                 `json:"msg"`
+			Continue bool                   `json:"continue"`
+			Metadata map[string]interface{} `json:"metadata"`
+			Stack    []string               `json:"stack"`
+			Cause    string                 `json:"cause"`
+		} `json:"err"`
+		Error struct {
+			Reason string `json:"reason"`
+			Wrap   string `json:"wrap"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("unmarshal %s: %v", buf.String(), err)
+	}
+	if got.Err.Reason != "InternalError.Slog" || got.Err.Msg != "dial db" || !got.Err.Continue ||
+		got.Err.Metadata["port"] != float64(3306) || len(got.Err.Stack) == 0 || got.Err.Cause != "dial timeout" {
+		t.Errorf("err attr: have %s", buf.String())
+	}
+	if got.Error.Reason != "InternalError.Slog" || got.Error.Wrap != "handler" {
+		t.Errorf("error attr: have %s", buf.String())
+	}
+}
+
+func TestLogValuer_Wrap(t *testing.T) {
+	se := xerrors.NewReasonError("FailedOperation.Slog", "inner")
+	tests := []struct {
+		input error
+		wrap  string
+	}{
+		{input: se},
+		{input: xerrors.Wrap(xerrors.Wrap(se, "service"), "handler"), wrap: "handler: service"},
+		{input: xerrors.WithStack(se)},
+		{input: xerrors.Join(errors.New("first"), xerrors.Wrap(se, "second")), wrap: "second"},
+		{input: xerrors.Wrap(xerrors.NewMultiError().Add("a", se), "batch"), wrap: "batch"},
+		{input: fmt.Errorf("call (%w) failed", se), wrap: "call () failed"},
+	}
+	for _, v := range tests {
+		var buf bytes.Buffer
+		slog.New(slog.NewJSONHandler(&buf, nil)).Error("msg", xerrors.Attr(v.input))
+		var got struct {
+			Error struct {
+				Reason string `json:"reason"`
+				Wrap   string `json:"wrap"`
+			} `json:"error"`
+		}
+		if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+			t.Fatalf("unmarshal %s: %v", buf.String(), err)
+		}
+		if got.Error.Reason != se.Reason || got.Error.Wrap != v.wrap {
+			t.Errorf("wrap of %v: have %q want %q", v.input, got.Error.Wrap, v.wrap)
+		}
+	}
+}
+
+func TestNewSlogHandler(t *testing.T) {
+	tests := []struct {
+		level  slog.Level
+		attr   slog.Attr
+		expect string
+	}{
+		{level: slog.LevelError, attr: slog.Any("err", xerrors.NewReasonError("FailedOperation.Slog", "").WithContinue()),
+			expect: "INFO"},
+		{level: slog.LevelError, attr: xerrors.Attr(xerrors.Wrap(xerrors.NewReasonError("FailedOperation.Slog", "").
+			WithContinue(), "wrap")), expect: "INFO"},
+		{level: slog.LevelWarn, attr: slog.Any("err", xerrors.NewReasonError("FailedOperation.Slog", "")),
+			expect: "WARN"},
+		{level: slog.LevelError, attr: slog.String("user", "u1"), expect: "ERROR"},
+		{level: slog.LevelError, attr: slog.Group("req", "id", 1, "err",
+			xerrors.NewReasonError("FailedOperation.Slog", "").WithContinue()), expect: "INFO"},
+		{level: slog.LevelError, attr: slog.Group("req", "err", xerrors.NewReasonError("FailedOperation.Slog", "")),
+			expect: "ERROR"},
+	}
+	for _, v := range tests {
+		var buf bytes.Buffer
+		logger := slog.New(xerrors.NewSlogHandler(slog.NewJSONHandler(&buf, nil)))
+		logger.LogAttrs(context.Background(), v.level, "msg", v.attr)
+		var got struct {
+			Level string `json:"level"`
+		}
+		if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+			t.Fatalf("unmarshal %s: %v", buf.String(), err)
+		}
+		if got.Level != v.expect {
+			t.Errorf("level of %v: have %s want %s", v.attr, got.Level, v.expect)
+		}
+	}
+
+	for _, v := range []struct {
+		with   []interface{}
+		group  string
+		expect string
+	}{
+		{with: []interface{}{"err", xerrors.NewReasonError("FailedOperation.Slog", "").WithContinue()}, expect: "INFO"},
+		{with: []interface{}{"err", xerrors.NewReasonError("FailedOperation.Slog", "").WithContinue()}, group: "g",
+			expect: "INFO"},
+		{with: []interface{}{"err", xerrors.NewReasonError("FailedOperation.Slog", "")}, expect: "ERROR"},
+		{with: []interface{}{"user", "u1"}, expect: "ERROR"},
+	} {
+		var buf bytes.Buffer
+		logger := slog.New(xerrors.NewSlogHandler(slog.NewJSONHandler(&buf, nil))).With(v.with...)
+		if len(v.group) > 0 {
+			logger = logger.WithGroup(v.group)
+		}
+		logger.Error("msg", "user", "u2")
+		var got struct {
+			Level string `json:"level"`
+		}
+		if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+			t.Fatalf("unmarshal %s: %v", buf.String(), err)
+		}
+		if got.Level != v.expect {
+			t.Errorf("level of With(%v): have %s want %s", v.with, got.Level, v.expect)
+		}
+	}
+
+	var buf bytes.Buffer
+	logger := slog.New(xerrors.NewSlogHandler(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelWarn})))
+	logger.Error("msg", "err", xerrors.NewReasonError("FailedOperation.Slog", "").WithContinue())
+	if buf.Len() != 0 {
+		t.Errorf("downgraded record below handler level: have %s", buf.String())
+	}
+}
@@ -0,0 +1,76 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/codermuhao/tools/xerrors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestReasonError_GRPCStatus(t *testing.T) {
+	tests := []struct {
+		input *xerrors.ReasonError
+		code  codes.Code
+	}{
+		{
+			input: xerrors.NewReasonError("ResourceNotFound.UserNotFound", "user not found"),
+			code:  codes.NotFound,
+		},
+		{
+			input: xerrors.NewReasonError("InvalidParameter", "bad id").WithContinue(),
+			code:  codes.InvalidArgument,
+		},
+		{
+			input: xerrors.NewReasonError("UserPasswordError", "wrong password").WithMetadata(map[string]interface{}{
+				"name":  "foo",
+				"count": float64(3),
+				"ok":    true,
+				"tags":  []interface{}{"a", "b"},
+			}),
+			code: codes.Unknown,
+		},
+		{
+			input: xerrors.NewReasonError("InvalidParameter.Reserved", "reserved keys").WithMetadata(map[string]interface{}{
+				"xerrors.continue": "true",
+				"xerrors.retry":    "transient",
+				"xerrors.typed":    "not json",
+			}),
+			code: codes.InvalidArgument,
+		},
+	}
+	for _, v := range tests {
+		st, ok := status.FromError(v.input)
+		if !ok {
+			t.Fatalf("status.FromError(%v): not a status error", v.input)
+		}
+		if got, want := st.Code(), v.code; got != want {
+			t.Errorf("code(%v): have %v want %v", v.input, got, want)
+		}
+		got := xerrors.FromStatus(st)
+		if !sameReasonError(got, v.input) {
+			t.Errorf("round trip(%v):\nhave %v\nwant %v", v.input, got, v.input)
+		}
+		if got := xerrors.Parse(st.Err()); !sameReasonError(got, v.input) {
+			t.Errorf("parse(%v):\nhave %v\nwant %v", v.input, got, v.input)
+		}
+	}
+}
+
+func TestToStatus(t *testing.T) {
+	xerrors.RegisterGRPCCode("FailedOperation.Locked", codes.Aborted)
+	tests := []struct {
+		input error
+		code  codes.Code
+	}{
+		{input: xerrors.Wrap(xerrors.NewReasonError("FailedOperation.Locked", "locked"), "wrap"), code: codes.Aborted},
+		{input: xerrors.NewReasonError("FailedOperation.Other", "other"), code: codes.FailedPrecondition},
+		{input: status.Error(codes.Unavailable, "down"), code: codes.Unavailable},
+		{input: xerrors.New("plain"), code: codes.Unknown},
+	}
+	for _, v := range tests {
+		if got, want := xerrors.ToStatus(v.input).Code(), v.code; got != want {
+			t.Errorf("ToStatus(%v): have %v want %v", v.input, got, want)
+		}
+	}
+}
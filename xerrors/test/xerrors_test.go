@@ -0,0 +1,69 @@
+package test
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/codermuhao/tools/xerrors"
+	"github.com/pkg/errors"
+)
+
+// sameReasonError 比较两个ReasonError的导出字段，忽略cause和调用栈
+func sameReasonError(a, b *xerrors.ReasonError) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Reason == b.Reason && a.Msg == b.Msg && a.Continue == b.Continue &&
+		reflect.DeepEqual(a.Metadata, b.Metadata)
+}
+
+func TestWrapReason(t *testing.T) {
+	cause := errors.Wrap(io.EOF, "read body")
+	err := xerrors.Wrap(xerrors.WrapReason(cause, "FailedOperation.ReadBody", "read body failed"), "handler")
+
+	if !errors.Is(err, io.EOF) {
+		t.Errorf("errors.Is(%v, io.EOF): have false want true", err)
+	}
+	if !errors.Is(err, xerrors.NewReasonError("FailedOperation.ReadBody", "")) {
+		t.Errorf("errors.Is(%v, FailedOperation.ReadBody): have false want true", err)
+	}
+	se := new(xerrors.ReasonError)
+	if !errors.As(err, &se) {
+		t.Fatalf("errors.As(%v): have false want true", err)
+	}
+	if got, want := se.Unwrap(), cause; got != want {
+		t.Errorf("unwrap: have %v want %v", got, want)
+	}
+	if len(se.StackTrace()) == 0 {
+		t.Errorf("stack trace: have none")
+	}
+	if got := xerrors.WrapReason(nil, "FailedOperation", "").Unwrap(); got != nil {
+		t.Errorf("unwrap nil cause: have %v want nil", got)
+	}
+}
+
+func TestReasonError_Format(t *testing.T) {
+	err := xerrors.WrapReasonf(errors.New("dial timeout"), "InternalError.Dial", "dial %s", "db").
+		WithMetadata(map[string]interface{}{"host": "db"}).WithContinue()
+	if got, want := fmt.Sprintf("%v", err), err.Error(); got != want {
+		t.Errorf("%%v: have %s want %s", got, want)
+	}
+	if got, want := fmt.Sprintf("%s", err), err.Error(); got != want {
+		t.Errorf("%%s: have %s want %s", got, want)
+	}
+	got := fmt.Sprintf("%+v", err)
+	for _, want := range []string{
+		"InternalError.Dial: dial db",
+		"continue: true",
+		"metadata: map[host:db]",
+		"test.TestReasonError_Format",
+		"cause: dial timeout",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("%%+v: have %s\nwant contains %s", got, want)
+		}
+	}
+}
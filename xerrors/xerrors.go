@@ -3,120 +3,237 @@ package xerrors
 
 import (
 	"fmt"
+	"io"
 
 	"github.com/codermuhao/tools/xjson"
 	"github.com/pkg/errors"
-	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/status"
 
 	microerrors "github.com/asim/go-micro/v3/errors"
+	jsoniter "github.com/json-iterator/go"
 )
 
 var unknown = "UNKNOWN_ERROR"
 
 // ReasonError 带reason和元信息的error结构
 type ReasonError struct {
-	Msg      string                 `json:"msg"`
-	Reason   string                 `json:"reason"`
-	Continue bool                   `json:"continue"`
-	Metadata map[string]interface{} `json:"metadata"`
+	Msg      string   `json:"msg"`
+	Reason   string   `json:"reason"`
+	Continue bool     `json:"continue"`
+	Metadata Metadata `json:"metadata"`
+	Retry    *Retry   `json:"retry,omitempty"`
+
+	cause error
+	stack *stack
 }
 
 // NewReasonError returns an error object for the reason, message.
+// NewReasonError also records the stack trace at the point it was called.
 func NewReasonError(reason string, message string) *ReasonError {
-	return &ReasonError{
-		Reason:   reason,
-		Msg:      message,
-		Metadata: make(map[string]interface{}),
-	}
+	return notify(StageNew, newReasonError(1, nil, reason, message))
 }
 
 // NewReasonErrorf NewReasonError(reason fmt.Sprintf(format, args...))
 func NewReasonErrorf(reason, format string, args ...interface{}) *ReasonError {
-	return NewReasonError(reason, fmt.Sprintf(format, args...))
+	return notify(StageNew, newReasonError(1, nil, reason, fmt.Sprintf(format, args...)))
+}
+
+// WrapReason returns an error object for the reason, message with err as its cause.
+// WrapReason also records the stack trace at the point it was called.
+// If err is nil, the returned error has no cause.
+func WrapReason(err error, reason, message string) *ReasonError {
+	return notify(StageNew, newReasonError(1, err, reason, message))
+}
+
+// WrapReasonf WrapReason(err, reason, fmt.Sprintf(format, args...))
+func WrapReasonf(err error, reason, format string, args ...interface{}) *ReasonError {
+	return notify(StageNew, newReasonError(1, err, reason, fmt.Sprintf(format, args...)))
+}
+
+func newReasonError(skip int, cause error, reason, message string) *ReasonError {
+	return &ReasonError{
+		Reason:   reason,
+		Msg:      message,
+		Metadata: make(Metadata),
+		cause:    cause,
+		stack:    callers(skip + 1),
+	}
 }
 
 // Parse try to convert an error to *Error.
-// It supports wrapped errors.
+// It walks the error tree built by Wrap, fmt.Errorf with one or more %w, Join and MultiError
+// breadth first, the outermost *ReasonError, grpc status, go-micro error or xjson.DecodeError is converted,
+// errors at the same depth are tried in Unwrap order. For a *MultiError the first contained error is returned.
 func Parse(err error) *ReasonError {
 	if err == nil {
 		return nil
 	}
-	if se := new(ReasonError); errors.As(err, &se) {
-		return se
-	}
-	gs, ok := status.FromError(err)
-	if ok {
-		for _, detail := range gs.Details() {
-			switch d := detail.(type) {
-			case *errdetails.ErrorInfo:
-				md := make(map[string]interface{}, len(d.Metadata))
-				for k, v := range d.Metadata {
-					md[k] = v
-				}
-				return NewReasonError(d.Reason, gs.Message()).WithMetadata(md)
+	var (
+		se *ReasonError
+		gs error
+	)
+	walk(err, func(e error) bool {
+		switch x := e.(type) {
+		case *ReasonError:
+			se = x
+		case *microerrors.Error:
+			se = FromMicroError(x)
+		case *xjson.DecodeError:
+			se = FromDecodeError(x)
+		case interface{ GRPCStatus() *status.Status }:
+			// 没有ErrorInfo的grpc status
+			if se = fromErrorInfo(x.GRPCStatus()); se == nil {
+				gs = e
 			}
 		}
-		return NewReasonError(unknown, err.Error())
+		return se != nil || gs != nil
+	})
+	if se != nil {
+		return se
 	}
-	switch err.(type) {
-	case *microerrors.Error:
-		se := new(ReasonError)
-		if err := xjson.Unmarshal([]byte(err.(*microerrors.Error).Detail), &se); err == nil {
-			return se
+	if gs != nil {
+		return newReasonError(0, nil, unknown, gs.Error())
+	}
+	se = new(ReasonError)
+	if err := xjson.Unmarshal([]byte(err.Error()), &se); err == nil {
+		return se
+	}
+	if me := parseMultiJSON(err.Error()); me != nil {
+		return me.Items[0].Error
+	}
+	return newReasonError(0, nil, unknown, err.Error())
+}
+
+// findReasonError 按Parse的顺序查找错误树中最外层的*ReasonError
+func findReasonError(err error) (*ReasonError, bool) {
+	var se *ReasonError
+	ok := walk(err, func(e error) bool {
+		se, _ = e.(*ReasonError)
+		return se != nil
+	})
+	return se, ok
+}
+
+// walk 广度优先遍历错误树，同一层按Unwrap的顺序，fn返回true时停止并返回true
+func walk(err error, fn func(error) bool) bool {
+	for queue := []error{err}; len(queue) > 0; queue = queue[1:] {
+		e := queue[0]
+		if e == nil {
+			continue
 		}
-	default:
-		se := new(ReasonError)
-		if err := xjson.Unmarshal([]byte(err.Error()), &se); err == nil {
-			return se
+		if fn(e) {
+			return true
 		}
+		queue = append(queue, UnwrapAll(e)...)
 	}
-	return NewReasonError(unknown, err.Error())
+	return false
 }
 
-// WithMetadata with an MD formed by the mapping of key, value.
+// WithMetadata returns a copy of the error with an MD formed by the mapping of key, value.
+// The values are normalized as described in Metadata.
 func (e *ReasonError) WithMetadata(md map[string]interface{}) *ReasonError {
+	se := e.clone()
 	for k, v := range md {
-		e.Metadata[k] = v
+		se.Metadata[k] = normalizeValue(v)
 	}
-	return e
+	return se
 }
 
-// WithContinue 设置continue字段为true表示此类错误是业务正常流程错
+// WithContinue 返回continue字段为true的副本，表示此类错误是业务正常流程错
 // 该标记可以作为是否记录日志等操作的依据
 func (e *ReasonError) WithContinue() *ReasonError {
-	e.Continue = true
-	return e
+	se := e.clone()
+	se.Continue = true
+	return se
 }
 
-// Continue returns the 'continue' flag.
-func Continue(err error) bool {
-	if err == nil {
-		return true
-	}
-	if se := Parse(err); err != nil {
-		return se.Continue
+// WithCause returns a copy of the error with err as its underlying cause.
+func (e *ReasonError) WithCause(err error) *ReasonError {
+	se := e.clone()
+	se.cause = err
+	return se
+}
+
+// clone 复制error，Metadata单独复制一份，保证With*系列方法不修改原error
+func (e *ReasonError) clone() *ReasonError {
+	se := *e
+	se.Metadata = e.Metadata.clone()
+	return &se
+}
+
+// Unwrap returns the underlying cause of the error, so errors.Is and errors.As
+// walk through it.
+func (e *ReasonError) Unwrap() error {
+	return e.cause
+}
+
+// StackTrace returns the stack recorded when the error was constructed.
+// It makes *ReasonError a pkg/errors stackTracer.
+func (e *ReasonError) StackTrace() errors.StackTrace {
+	return e.stack.StackTrace()
+}
+
+// Format formats the error according to the fmt.Formatter interface.
+//
+//	%s, %v  the json form, same as Error()
+//	%q      the quoted json form
+//	%+v     reason, message, continue flag, metadata, the stack trace and the cause with its own %+v
+func (e *ReasonError) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			fmt.Fprintf(s, "%s: %s", e.Reason, e.Msg)
+			if e.Continue {
+				io.WriteString(s, "\ncontinue: true")
+			}
+			if len(e.Metadata) > 0 {
+				fmt.Fprintf(s, "\nmetadata: %v", e.Metadata)
+			}
+			e.stack.Format(s, verb)
+			if e.cause != nil {
+				fmt.Fprintf(s, "\ncause: %+v", e.cause)
+			}
+			return
+		}
+		fallthrough
+	case 's':
+		io.WriteString(s, e.Error())
+	case 'q':
+		fmt.Fprintf(s, "%q", e.Error())
 	}
-	return false
 }
 
 // Error implements error interface
+// The json form is encoded by hand, see encode.
 func (e *ReasonError) Error() string {
-	data, err := xjson.Marshal(e)
-	if err != nil {
-		data, _ = xjson.Marshal(NewReasonError(unknown, err.Error()))
+	stream := jsonAPI.BorrowStream(nil)
+	defer jsonAPI.ReturnStream(stream)
+	e.encode(stream)
+	if stream.Error != nil {
+		data, _ := xjson.Marshal(newReasonError(0, nil, unknown, stream.Error.Error()))
+		return string(data)
 	}
-	return string(data)
+	return string(stream.Buffer())
 }
 
 // Is matches each error in the chain with the target value.
+// A Category target matches the category and all its child reasons.
+// Typed errors are compared by reason directly, only an error whose message is a json object
+// is parsed, and only its reason field is read.
 func (e *ReasonError) Is(err error) bool {
-	if se := new(ReasonError); errors.As(err, &se) {
+	if se, ok := err.(*ReasonError); ok {
 		return se.Reason == e.Reason
 	}
-	se := new(ReasonError)
-	if err := xjson.Unmarshal([]byte(err.Error()), &se); err == nil {
+	if c, ok := err.(Category); ok {
+		return c.Match(e.Reason)
+	}
+	if se, ok := findReasonError(err); ok {
 		return se.Reason == e.Reason
 	}
-	return false
+	s := err.Error()
+	if len(s) == 0 || s[0] != '{' {
+		return false
+	}
+	reason := jsonAPI.Get([]byte(s), "reason")
+	return reason.LastError() == nil && reason.ValueType() == jsoniter.StringValue && reason.ToString() == e.Reason
 }
@@ -0,0 +1,191 @@
+package xjson
+
+import (
+	"bytes"
+	stdjson "encoding/json"
+	"reflect"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoregistry"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// Naming proto message字段名的风格
+type Naming int
+
+const (
+	// ProtoNames 使用proto中定义的字段名，例如"outer_string"，默认风格
+	ProtoNames Naming = iota
+	// JSONNames 使用lowerCamelCase的json_name，例如"outerString"
+	JSONNames
+)
+
+// EnumStyle proto枚举值的输出风格
+type EnumStyle int
+
+const (
+	// EnumNumbers 枚举输出为数字，默认风格
+	EnumNumbers EnumStyle = iota
+	// EnumNames 枚举输出为枚举值的名字
+	EnumNames
+)
+
+type options struct {
+	naming          Naming
+	enumStyle       EnumStyle
+	emitUnpopulated bool
+	int64AsString   bool
+	indent          string
+	discardUnknown  bool
+	resolver        Resolver
+}
+
+// Resolver 查找google.protobuf.Any中的message类型，*protoregistry.Types实现了该接口
+type Resolver interface {
+	protoregistry.MessageTypeResolver
+	protoregistry.ExtensionTypeResolver
+}
+
+// Option Codec的配置项
+type Option func(*options)
+
+// WithNaming sets the field name style of proto messages, the default is ProtoNames.
+func WithNaming(n Naming) Option {
+	return func(o *options) {
+		o.naming = n
+	}
+}
+
+// WithEnumStyle sets the enum value style of proto messages, the default is EnumNumbers.
+func WithEnumStyle(s EnumStyle) Option {
+	return func(o *options) {
+		o.enumStyle = s
+	}
+}
+
+// WithEmitUnpopulated sets whether unpopulated fields of proto messages are emitted, the default is true.
+func WithEmitUnpopulated(emit bool) Option {
+	return func(o *options) {
+		o.emitUnpopulated = emit
+	}
+}
+
+// WithInt64AsString sets whether 64-bit integers of proto messages are quoted as protojson does,
+// the default is true. When false messages are marshaled by the same encoder as XMarshalPB,
+// the output is the same as protojson except that 64-bit integers are numbers and there is no whitespace.
+// Plain Go values are not affected, use the ",string" json tag option for them.
+func WithInt64AsString(quote bool) Option {
+	return func(o *options) {
+		o.int64AsString = quote
+	}
+}
+
+// WithIndent sets the indent of the output, the default is "" which means no indent,
+// proto messages are then output as protojson does.
+func WithIndent(indent string) Option {
+	return func(o *options) {
+		o.indent = indent
+	}
+}
+
+// WithDiscardUnknown sets whether unknown fields are ignored when unmarshaling, the default is true.
+func WithDiscardUnknown(discard bool) Option {
+	return func(o *options) {
+		o.discardUnknown = discard
+	}
+}
+
+// WithResolver sets the resolver of the types in google.protobuf.Any, the default is protoregistry.GlobalTypes.
+func WithResolver(r Resolver) Option {
+	return func(o *options) {
+		o.resolver = r
+	}
+}
+
+// Codec 可配置的json编解码器，自动判断使用通用json还是protobuf结构的json操作
+// Codec创建后只读，可以并发使用
+type Codec struct {
+	opts      options
+	marshal   protojson.MarshalOptions
+	unmarshal protojson.UnmarshalOptions
+	json      jsoniter.API
+}
+
+// NewCodec returns a Codec configured by opts, NewCodec() is the codec used by Marshal and Unmarshal.
+func NewCodec(opts ...Option) *Codec {
+	o := options{emitUnpopulated: true, int64AsString: true, discardUnknown: true, resolver: protoregistry.GlobalTypes}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return &Codec{
+		opts: o,
+		marshal: protojson.MarshalOptions{
+			EmitUnpopulated: o.emitUnpopulated,
+			UseProtoNames:   o.naming == ProtoNames,
+			UseEnumNumbers:  o.enumStyle == EnumNumbers,
+			Resolver:        o.resolver,
+		},
+		// 宽松解码器只在protojson失败时使用，"userID"这类字段名只在此时才按宽松规则匹配
+		unmarshal: protojson.UnmarshalOptions{DiscardUnknown: o.discardUnknown, Resolver: o.resolver},
+		json: jsoniter.Config{
+			EscapeHTML:             true,
+			SortMapKeys:            true,
+			ValidateJsonRawMessage: true,
+			DisallowUnknownFields:  !o.discardUnknown,
+		}.Froze(),
+	}
+}
+
+// Marshal json marshal
+func (c *Codec) Marshal(v interface{}) ([]byte, error) {
+	var (
+		data []byte
+		err  error
+	)
+	m, ok := v.(proto.Message)
+	switch {
+	case !ok:
+		data, err = c.json.Marshal(v)
+	case !c.opts.int64AsString:
+		data, err = c.marshalPB(m)
+	default:
+		data, err = c.marshal.Marshal(m)
+	}
+	if err != nil || len(c.opts.indent) == 0 {
+		return data, err
+	}
+	var buf bytes.Buffer
+	if err := stdjson.Indent(&buf, data, "", c.opts.indent); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Unmarshal json unmarshal
+// Proto messages rejected by protojson are decoded again by a lenient decoder,
+// which accepts e.g. "123" for an int field, see lenientDecoder.
+func (c *Codec) Unmarshal(data []byte, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	for rv := rv; rv.Kind() == reflect.Ptr; {
+		if rv.IsNil() {
+			rv.Set(reflect.New(rv.Type().Elem()))
+		}
+		rv = rv.Elem()
+	}
+	if m, ok := v.(proto.Message); ok {
+		return c.fallbackUnmarshal(c.unmarshal.Unmarshal(data, m), data, m)
+	} else if m, ok := reflect.Indirect(rv).Interface().(proto.Message); ok {
+		return c.fallbackUnmarshal(c.unmarshal.Unmarshal(data, m), data, m)
+	}
+	return c.json.Unmarshal(data, v)
+}
+
+// fallbackUnmarshal 为了兼容类似字段定义为int，而收到的是string的情况，protojson失败时宽松解码
+func (c *Codec) fallbackUnmarshal(err error, data []byte, m proto.Message) error {
+	if err == nil {
+		return nil
+	}
+	return c.unmarshalLenient(data, m)
+}
@@ -0,0 +1,62 @@
+package xjson
+
+import (
+	stdjson "encoding/json"
+	"strconv"
+	"strings"
+)
+
+// FieldError 单个字段的解码错误
+type FieldError struct {
+	// Path 出错字段的json路径，例如"inner.inner_int"、"inners[1]"，顶层为""
+	Path string
+	// Field Path中的proto字段名，不含数组下标，到第一个map key为止，未知字段为""，
+	// 例如Path为"inners[1].inner_int"、"counts.a"时分别为"inners.inner_int"、"counts"
+	// Field不含输入中的任意内容，可以用作metric label等取值有限的场景
+	Field string
+	// Expected 期望的类型，例如"int32"、"enum test.Status"、"object"
+	Expected string
+	// Value 输入中实际值的片段，最多32字节
+	Value string
+	// Offset 实际值在输入中的字节offset
+	Offset int64
+	// Message 错误描述
+	Message string
+}
+
+// Error implements error interface
+func (e *FieldError) Error() string {
+	var b strings.Builder
+	b.WriteString("xjson: ")
+	if len(e.Path) > 0 {
+		b.WriteString(e.Path)
+		b.WriteString(": ")
+	}
+	b.WriteString(e.Message)
+	b.WriteString(" (offset ")
+	b.WriteString(strconv.FormatInt(e.Offset, 10))
+	b.WriteString(")")
+	return b.String()
+}
+
+// DecodeError proto message宽松解码失败的错误，包含全部字段错误，按在输入中出现的顺序排列
+type DecodeError struct {
+	Errors []*FieldError
+}
+
+// Error implements error interface
+func (e *DecodeError) Error() string {
+	msgs := make([]string, 0, len(e.Errors))
+	for _, fe := range e.Errors {
+		msgs = append(msgs, fe.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// syntaxOffset json语法错误的字节offset，SyntaxError.Offset为非法字符之后的位置，截断时为输入的长度
+func syntaxOffset(se *stdjson.SyntaxError) int64 {
+	if strings.HasPrefix(se.Error(), "invalid character") && se.Offset > 0 {
+		return se.Offset - 1
+	}
+	return se.Offset
+}
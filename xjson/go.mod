@@ -4,7 +4,6 @@ go 1.16
 
 require (
 	github.com/golang/protobuf v1.5.0
-	github.com/json-iterator/go v1.1.11
-	github.com/mitchellh/mapstructure v1.4.3
+	github.com/json-iterator/go v1.1.12
 	google.golang.org/protobuf v1.27.1
 )
@@ -0,0 +1,548 @@
+package xjson
+
+import (
+	"bytes"
+	"encoding/base64"
+	stdjson "encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	pref "google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// wellKnownTypes 交给protojson解码的well-known types，wrappers单独宽松处理
+var wellKnownTypes = map[pref.FullName]bool{
+	"google.protobuf.Any":       true,
+	"google.protobuf.Timestamp": true,
+	"google.protobuf.Duration":  true,
+	"google.protobuf.FieldMask": true,
+	"google.protobuf.Struct":    true,
+	"google.protobuf.Value":     true,
+	"google.protobuf.ListValue": true,
+	"google.protobuf.Empty":     true,
+}
+
+// wrapperTypes google.protobuf.*Value wrappers，json形式即value字段本身
+var wrapperTypes = map[pref.FullName]bool{
+	"google.protobuf.DoubleValue": true,
+	"google.protobuf.FloatValue":  true,
+	"google.protobuf.Int64Value":  true,
+	"google.protobuf.UInt64Value": true,
+	"google.protobuf.Int32Value":  true,
+	"google.protobuf.UInt32Value": true,
+	"google.protobuf.BoolValue":   true,
+	"google.protobuf.StringValue": true,
+	"google.protobuf.BytesValue":  true,
+}
+
+// lenientDecoder 基于protoreflect的宽松proto json解码器，protojson解码失败时使用
+//
+// 相比protojson额外接受：
+//
+//	数字字段   带引号的数字，例如"123"、"1e3"
+//	bool字段   "true"/"false"/"1"/"0"以及数字0/1
+//	string字段 数字和bool，按字面值转为string
+//	枚举字段   数字形式的字符串，例如"2"
+//	message字段 ""等同于null
+//	字段名     proto名、json_name以及忽略大小写和下划线后相同的名字，例如"userID"匹配user_id
+//
+// 字段值错误不会中断解码，全部记录后以*DecodeError返回；json语法错误会立即返回
+type lenientDecoder struct {
+	c    *Codec
+	d    *stdjson.Decoder
+	data []byte
+	// base data在原始输入中的offset，解码well-known types的子decoder使用
+	base int64
+	errs []*FieldError
+}
+
+// errAbort json语法错误，已记录到errs中，停止解码
+var errAbort = errors.New("xjson: abort")
+
+// location 值在输入中的位置
+type location struct {
+	// path json路径，例如"inners[1].inner_int"、"counts.a"
+	path string
+	// field path中的proto字段名，不含数组下标，到第一个map key为止，例如"inners.inner_int"、"counts"
+	field string
+	// inMap 已经进入map的value，field不再增长
+	inMap bool
+}
+
+// child message字段fd的位置，key为输入中的字段名
+func (l location) child(key string, fd pref.FieldDescriptor) location {
+	c := location{path: joinPath(l.path, key), field: l.field, inMap: l.inMap}
+	if !l.inMap {
+		c.field = joinPath(l.field, string(fd.Name()))
+	}
+	return c
+}
+
+// index 数组第i个元素的位置
+func (l location) index(i int) location {
+	return location{path: fmt.Sprintf("%s[%d]", l.path, i), field: l.field, inMap: l.inMap}
+}
+
+// mapKey map中key对应的value的位置
+func (l location) mapKey(key string) location {
+	return location{path: joinPath(l.path, key), field: l.field, inMap: true}
+}
+
+// unmarshalLenient 宽松解码data到m，m会先被清空
+func (c *Codec) unmarshalLenient(data []byte, m proto.Message) error {
+	proto.Reset(m)
+	d := newLenientDecoder(c, data, 0)
+	if _, err := d.message(m.ProtoReflect(), location{}); err == nil {
+		if tok, start, err := d.token(location{}); err == nil {
+			d.mismatch(location{}, "end of input", tok, start)
+		}
+	}
+	if len(d.errs) > 0 {
+		return &DecodeError{Errors: d.errs}
+	}
+	return nil
+}
+
+func newLenientDecoder(c *Codec, data []byte, base int64) *lenientDecoder {
+	d := &lenientDecoder{c: c, d: stdjson.NewDecoder(bytes.NewReader(data)), data: data, base: base}
+	d.d.UseNumber()
+	return d
+}
+
+// token 读取下一个token及其起始offset，io.EOF之外的读取错误记录后返回errAbort
+func (d *lenientDecoder) token(loc location) (stdjson.Token, int64, error) {
+	start := d.d.InputOffset()
+	tok, err := d.d.Token()
+	for start < int64(len(d.data)) && strings.IndexByte(" \t\r\n:,", d.data[start]) >= 0 {
+		start++
+	}
+	if err == io.EOF {
+		return nil, start, err
+	}
+	if err != nil {
+		return nil, start, d.abort(loc, start, err)
+	}
+	return tok, start, nil
+}
+
+// abort 记录json语法错误并返回errAbort，err已经是errAbort时不重复记录
+func (d *lenientDecoder) abort(loc location, start int64, err error) error {
+	if err == errAbort {
+		return err
+	}
+	if se, ok := err.(*stdjson.SyntaxError); ok {
+		start = syntaxOffset(se)
+	}
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	d.errs = append(d.errs, &FieldError{
+		Path:     loc.path,
+		Field:    loc.field,
+		Expected: "json",
+		Offset:   d.base + start,
+		Message:  err.Error(),
+	})
+	return errAbort
+}
+
+// fail 记录一个字段错误，值为输入中[start, 当前位置)的内容
+func (d *lenientDecoder) fail(loc location, expected string, start int64, message string) {
+	value := snippet(d.data[start:d.d.InputOffset()])
+	if len(message) == 0 {
+		message = fmt.Sprintf("expected %s, got %s", expected, value)
+	}
+	d.errs = append(d.errs, &FieldError{
+		Path:     loc.path,
+		Field:    loc.field,
+		Expected: expected,
+		Value:    value,
+		Offset:   d.base + start,
+		Message:  message,
+	})
+}
+
+// mismatch 记录类型不匹配的错误，tok为对象或数组的开始时跳过整个值
+func (d *lenientDecoder) mismatch(loc location, expected string, tok stdjson.Token, start int64) error {
+	if tok == stdjson.Delim('{') || tok == stdjson.Delim('[') {
+		for depth := 1; depth > 0; {
+			tok, _, err := d.token(loc)
+			if err != nil {
+				return d.abort(loc, start, err)
+			}
+			switch tok {
+			case stdjson.Delim('{'), stdjson.Delim('['):
+				depth++
+			case stdjson.Delim('}'), stdjson.Delim(']'):
+				depth--
+			}
+		}
+	}
+	d.fail(loc, expected, start, "")
+	return nil
+}
+
+// message 解码一个message，null和""时返回false
+func (d *lenientDecoder) message(m pref.Message, loc location) (bool, error) {
+	md := m.Descriptor()
+	if wrapperTypes[md.FullName()] || wellKnownTypes[md.FullName()] {
+		return d.wellKnown(m, loc)
+	}
+	tok, start, err := d.token(loc)
+	if err != nil {
+		return false, d.abort(loc, start, err)
+	}
+	if tok == nil || tok == "" {
+		return false, nil
+	}
+	if tok != stdjson.Delim('{') {
+		return false, d.mismatch(loc, "object", tok, start)
+	}
+	oneofs := make(map[pref.OneofDescriptor]pref.FieldDescriptor)
+	for d.d.More() {
+		tok, start, err := d.token(loc)
+		if err != nil {
+			return false, d.abort(loc, start, err)
+		}
+		key := tok.(string)
+		fd := lookupField(md, key)
+		if fd == nil {
+			// 未知字段的名字来自输入，不计入field
+			floc := location{path: joinPath(loc.path, key)}
+			var raw stdjson.RawMessage
+			if err := d.d.Decode(&raw); err != nil {
+				return false, d.abort(floc, start, err)
+			}
+			if !d.c.opts.discardUnknown {
+				d.fail(floc, "", start, "unknown field")
+			}
+			continue
+		}
+		floc := loc.child(key, fd)
+		set, err := d.field(m, fd, floc)
+		if err != nil {
+			return false, err
+		}
+		if od := fd.ContainingOneof(); set && od != nil && !od.IsSynthetic() {
+			if prev, ok := oneofs[od]; ok {
+				d.fail(floc, "", start, fmt.Sprintf("oneof %s is already set by %s", od.Name(), prev.Name()))
+			}
+			oneofs[od] = fd
+		}
+	}
+	if _, _, err := d.token(loc); err != nil {
+		return false, d.abort(loc, start, err)
+	}
+	return true, nil
+}
+
+// field 解码message的一个字段，null时不设置并返回false
+func (d *lenientDecoder) field(m pref.Message, fd pref.FieldDescriptor, loc location) (bool, error) {
+	switch {
+	case fd.IsList():
+		return d.list(m.Mutable(fd).List(), fd, loc)
+	case fd.IsMap():
+		return d.mapValue(m.Mutable(fd).Map(), fd, loc)
+	case fd.Message() != nil:
+		v := m.NewField(fd)
+		set, err := d.message(v.Message(), loc)
+		if set {
+			m.Set(fd, v)
+		}
+		return set, err
+	}
+	v, set, err := d.scalar(fd, loc, true)
+	if set {
+		m.Set(fd, v)
+	}
+	return set, err
+}
+
+func (d *lenientDecoder) list(l pref.List, fd pref.FieldDescriptor, loc location) (bool, error) {
+	tok, start, err := d.token(loc)
+	if err != nil {
+		return false, d.abort(loc, start, err)
+	}
+	if tok == nil {
+		return false, nil
+	}
+	if tok != stdjson.Delim('[') {
+		return false, d.mismatch(loc, "array", tok, start)
+	}
+	for i := 0; d.d.More(); i++ {
+		iloc := loc.index(i)
+		if fd.Message() != nil {
+			v := l.NewElement()
+			if _, err := d.message(v.Message(), iloc); err != nil {
+				return false, err
+			}
+			l.Append(v)
+			continue
+		}
+		v, set, err := d.scalar(fd, iloc, false)
+		if err != nil {
+			return false, err
+		}
+		if set {
+			l.Append(v)
+		}
+	}
+	if _, _, err := d.token(loc); err != nil {
+		return false, d.abort(loc, start, err)
+	}
+	return l.Len() > 0, nil
+}
+
+func (d *lenientDecoder) mapValue(mm pref.Map, fd pref.FieldDescriptor, loc location) (bool, error) {
+	tok, start, err := d.token(loc)
+	if err != nil {
+		return false, d.abort(loc, start, err)
+	}
+	if tok == nil {
+		return false, nil
+	}
+	if tok != stdjson.Delim('{') {
+		return false, d.mismatch(loc, "object", tok, start)
+	}
+	kfd, vfd := fd.MapKey(), fd.MapValue()
+	for d.d.More() {
+		tok, kstart, err := d.token(loc)
+		if err != nil {
+			return false, d.abort(loc, kstart, err)
+		}
+		key := tok.(string)
+		kloc := loc.mapKey(key)
+		k, kerr := scalarFromString(kfd, key)
+		if kerr != nil {
+			d.fail(kloc, expectedType(kfd), kstart, "invalid map key: "+kerr.Error())
+		}
+		var (
+			v   pref.Value
+			set bool
+		)
+		if vfd.Message() != nil {
+			v = mm.NewValue()
+			_, err = d.message(v.Message(), kloc)
+			set = true
+		} else {
+			v, set, err = d.scalar(vfd, kloc, false)
+		}
+		if err != nil {
+			return false, err
+		}
+		if set && kerr == nil {
+			mm.Set(k.MapKey(), v)
+		}
+	}
+	if _, _, err := d.token(loc); err != nil {
+		return false, d.abort(loc, start, err)
+	}
+	return mm.Len() > 0, nil
+}
+
+// wellKnown wrappers按value字段宽松解码，其他well-known types交给protojson
+func (d *lenientDecoder) wellKnown(m pref.Message, loc location) (bool, error) {
+	start := d.d.InputOffset()
+	for start < int64(len(d.data)) && strings.IndexByte(" \t\r\n:,", d.data[start]) >= 0 {
+		start++
+	}
+	var raw stdjson.RawMessage
+	if err := d.d.Decode(&raw); err != nil {
+		return false, d.abort(loc, start, err)
+	}
+	md := m.Descriptor()
+	// google.protobuf.Value的null是合法的NullValue
+	if md.FullName() != "google.protobuf.Value" && (string(raw) == "null" || string(raw) == `""`) {
+		return false, nil
+	}
+	if wrapperTypes[md.FullName()] {
+		vfd := md.Fields().ByName("value")
+		sub := newLenientDecoder(d.c, raw, d.base+start)
+		v, set, err := sub.scalar(vfd, loc, true)
+		d.errs = append(d.errs, sub.errs...)
+		if set {
+			m.Set(vfd, v)
+		}
+		return set, err
+	}
+	opts := protojson.UnmarshalOptions{DiscardUnknown: d.c.opts.discardUnknown, Resolver: d.c.opts.resolver}
+	if err := opts.Unmarshal(raw, m.Interface()); err != nil {
+		d.fail(loc, string(md.FullName()), start, err.Error())
+		return false, nil
+	}
+	return true, nil
+}
+
+// scalar 解码一个非message的值，null时返回false，nullable为false时null记为错误
+func (d *lenientDecoder) scalar(fd pref.FieldDescriptor, loc location, nullable bool) (pref.Value, bool, error) {
+	tok, start, err := d.token(loc)
+	if err != nil {
+		return pref.Value{}, false, d.abort(loc, start, err)
+	}
+	var s string
+	switch x := tok.(type) {
+	case nil:
+		if !nullable {
+			d.fail(loc, expectedType(fd), start, "")
+		}
+		return pref.Value{}, false, nil
+	case stdjson.Number:
+		s = string(x)
+	case string:
+		s = x
+		if fd.Kind() != pref.StringKind && fd.Kind() != pref.BytesKind {
+			s = strings.TrimSpace(s)
+		}
+	case bool:
+		if fd.Kind() != pref.BoolKind && fd.Kind() != pref.StringKind {
+			return pref.Value{}, false, d.mismatch(loc, expectedType(fd), tok, start)
+		}
+		s = strconv.FormatBool(x)
+	default:
+		return pref.Value{}, false, d.mismatch(loc, expectedType(fd), tok, start)
+	}
+	v, err := scalarFromString(fd, s)
+	if err != nil {
+		d.fail(loc, expectedType(fd), start, "")
+		return pref.Value{}, false, nil
+	}
+	return v, true, nil
+}
+
+// expectedType 错误信息中字段期望的类型，例如"int32"、"enum test.Status"
+func expectedType(fd pref.FieldDescriptor) string {
+	if fd.Kind() == pref.EnumKind {
+		return "enum " + string(fd.Enum().FullName())
+	}
+	return fd.Kind().String()
+}
+
+// snippet 错误信息中值的片段，最多32字节
+func snippet(data []byte) string {
+	const max = 32
+	if len(data) <= max {
+		return string(data)
+	}
+	data = data[:max]
+	for len(data) > 0 && !utf8.Valid(data) {
+		data = data[:len(data)-1]
+	}
+	return string(data) + "..."
+}
+
+// scalarFromString 按字段类型解析字面值，用于数字、带引号的数字以及map的key
+func scalarFromString(fd pref.FieldDescriptor, s string) (pref.Value, error) {
+	switch fd.Kind() {
+	case pref.BoolKind:
+		b, err := strconv.ParseBool(s)
+		return pref.ValueOfBool(b), err
+	case pref.StringKind:
+		return pref.ValueOfString(s), nil
+	case pref.BytesKind:
+		b, err := decodeBytes(s)
+		return pref.ValueOfBytes(b), err
+	case pref.EnumKind:
+		if ev := fd.Enum().Values().ByName(pref.Name(s)); ev != nil {
+			return pref.ValueOfEnum(ev.Number()), nil
+		}
+		n, err := parseInt(s, 32)
+		if err != nil {
+			return pref.Value{}, fmt.Errorf("invalid value for enum %s: %q", fd.Enum().FullName(), s)
+		}
+		return pref.ValueOfEnum(pref.EnumNumber(n)), nil
+	case pref.Int32Kind, pref.Sint32Kind, pref.Sfixed32Kind:
+		n, err := parseInt(s, 32)
+		return pref.ValueOfInt32(int32(n)), err
+	case pref.Int64Kind, pref.Sint64Kind, pref.Sfixed64Kind:
+		n, err := parseInt(s, 64)
+		return pref.ValueOfInt64(n), err
+	case pref.Uint32Kind, pref.Fixed32Kind:
+		n, err := parseUint(s, 32)
+		return pref.ValueOfUint32(uint32(n)), err
+	case pref.Uint64Kind, pref.Fixed64Kind:
+		n, err := parseUint(s, 64)
+		return pref.ValueOfUint64(n), err
+	case pref.FloatKind:
+		f, err := strconv.ParseFloat(s, 32)
+		return pref.ValueOfFloat32(float32(f)), err
+	case pref.DoubleKind:
+		f, err := strconv.ParseFloat(s, 64)
+		return pref.ValueOfFloat64(f), err
+	}
+	return pref.Value{}, fmt.Errorf("unsupported kind %s", fd.Kind())
+}
+
+// parseInt 解析整数，接受值为整数的浮点数写法，例如"1e3"、"12.0"
+func parseInt(s string, bits int) (int64, error) {
+	n, err := strconv.ParseInt(s, 10, bits)
+	if err == nil {
+		return n, nil
+	}
+	f, ferr := strconv.ParseFloat(s, 64)
+	if ferr != nil || f != math.Trunc(f) || f < -math.Pow(2, float64(bits-1)) || f >= math.Pow(2, float64(bits-1)) {
+		return 0, fmt.Errorf("invalid int%d: %q", bits, s)
+	}
+	return int64(f), nil
+}
+
+// parseUint 解析无符号整数，接受值为整数的浮点数写法
+func parseUint(s string, bits int) (uint64, error) {
+	n, err := strconv.ParseUint(s, 10, bits)
+	if err == nil {
+		return n, nil
+	}
+	f, ferr := strconv.ParseFloat(s, 64)
+	if ferr != nil || f != math.Trunc(f) || f < 0 || f >= math.Pow(2, float64(bits)) {
+		return 0, fmt.Errorf("invalid uint%d: %q", bits, s)
+	}
+	return uint64(f), nil
+}
+
+// decodeBytes 依次尝试标准和URL安全的base64，带或不带padding
+func decodeBytes(s string) ([]byte, error) {
+	enc := base64.StdEncoding
+	if strings.ContainsAny(s, "-_") {
+		enc = base64.URLEncoding
+	}
+	if len(s)%4 != 0 {
+		enc = enc.WithPadding(base64.NoPadding)
+	}
+	return enc.DecodeString(s)
+}
+
+// lookupField 依次按proto名、json_name、忽略大小写和下划线的名字查找字段
+func lookupField(md pref.MessageDescriptor, key string) pref.FieldDescriptor {
+	fds := md.Fields()
+	if fd := fds.ByName(pref.Name(key)); fd != nil {
+		return fd
+	}
+	if fd := fds.ByJSONName(key); fd != nil {
+		return fd
+	}
+	norm := normalizeName(key)
+	for i := 0; i < fds.Len(); i++ {
+		if fd := fds.Get(i); normalizeName(string(fd.Name())) == norm {
+			return fd
+		}
+	}
+	return nil
+}
+
+func normalizeName(s string) string {
+	return strings.ToLower(strings.Replace(s, "_", "", -1))
+}
+
+// joinPath 拼接json路径，例如"inner.inner_int"
+func joinPath(path, key string) string {
+	if len(path) == 0 {
+		return key
+	}
+	return path + "." + key
+}
@@ -1,40 +1,89 @@
 package xjson
 
 import (
+	"bytes"
 	"encoding/base64"
+	stdjson "encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
 
+	jsoniter "github.com/json-iterator/go"
+	"google.golang.org/protobuf/encoding/protojson"
 	"google.golang.org/protobuf/proto"
 	pref "google.golang.org/protobuf/reflect/protoreflect"
 )
 
+// xpbCodec XMarshalPB使用的Codec，64位整数不转为string
+var xpbCodec = NewCodec(WithInt64AsString(false))
+
 // XMarshalPB protojson特殊版本，强烈建议不要使用！！！
-// 仅为了老代码保留，主要改动是不把64位整数转为string，但实际上这是有一定风险的，具体请见：
+// 仅为了老代码保留，和protojson的唯一区别是不把64位整数转为string，但实际上这是有一定风险的，具体请见：
 // https://stackoverflow.com/questions/53911502
+// 需要其他配置时使用NewCodec(WithInt64AsString(false), ...)
 func XMarshalPB(pb proto.Message) ([]byte, error) {
-	v := itrMessage(pb.ProtoReflect())
-	j, err := json.Marshal(v)
+	return xpbCodec.Marshal(pb)
+}
 
-	return j, err
+// XUnmarshalPB XMarshalPB的逆操作，64位整数同时接受数字和string
+func XUnmarshalPB(data []byte, pb proto.Message) error {
+	return xpbCodec.Unmarshal(data, pb)
 }
 
-func itrMessage(pm pref.Message) interface{} {
-	m := make(map[string]interface{})
-	fds := pm.Descriptor().Fields()
-	for i := 0; i < fds.Len(); {
-		fd := fds.Get(i)
+// pbEncoder 64位整数不转为string的proto json encoder，输出为紧凑格式，除此之外和protojson一致：
+// 字段按proto中的定义顺序输出，map的key排序，未设置的message为null，
+// well-known types使用各自的json形式，Any通过Resolver查找类型并输出"@type"
+type pbEncoder struct {
+	c *Codec
+	s *jsoniter.Stream
+}
 
-		if od := fd.ContainingOneof(); od != nil {
-			fd = pm.WhichOneof(od)
-			i += od.Fields().Len()
-			if fd == nil {
-				continue // unpopulated oneofs are not affected by EmitUnpopulated
-			}
-		} else {
-			i++
-		}
+// marshalPB 使用pbEncoder编码m
+func (c *Codec) marshalPB(m proto.Message) ([]byte, error) {
+	s := c.json.BorrowStream(nil)
+	defer c.json.ReturnStream(s)
+	e := &pbEncoder{c: c, s: s}
+	if err := e.message(m.ProtoReflect()); err != nil {
+		return nil, err
+	}
+	if s.Error != nil {
+		return nil, s.Error
+	}
+	return append([]byte(nil), s.Buffer()...), nil
+}
+
+func (e *pbEncoder) message(m pref.Message) error {
+	md := m.Descriptor()
+	switch {
+	case md.FullName() == "google.protobuf.Any":
+		return e.any(m)
+	case wrapperTypes[md.FullName()]:
+		fd := md.Fields().ByName("value")
+		return e.singular(m.Get(fd), fd)
+	case wellKnownTypes[md.FullName()]:
+		return e.wellKnown(m)
+	}
+	e.s.WriteObjectStart()
+	if err := e.fields(m, false); err != nil {
+		return err
+	}
+	e.s.WriteObjectEnd()
+	return nil
+}
 
-		val := pm.Get(fd)
-		if !pm.Has(fd) {
+// fields 按定义顺序输出m的字段，more表示之前已经输出过字段
+func (e *pbEncoder) fields(m pref.Message, more bool) error {
+	fds := m.Descriptor().Fields()
+	for i := 0; i < fds.Len(); i++ {
+		fd := fds.Get(i)
+		val := m.Get(fd)
+		if !m.Has(fd) {
+			// 未设置的oneof字段不受emitUnpopulated影响
+			if !e.c.opts.emitUnpopulated || fd.ContainingOneof() != nil {
+				continue
+			}
 			isProto2Scalar := fd.Syntax() == pref.Proto2 && fd.Default().IsValid()
 			isSingularMessage := fd.Cardinality() != pref.Repeated && fd.Message() != nil
 			if isProto2Scalar || isSingularMessage {
@@ -42,34 +91,51 @@ func itrMessage(pm pref.Message) interface{} {
 				val = pref.Value{}
 			}
 		}
-
-		name := string(fd.Name())
-		// Use type name for group field name.
-		if fd.Kind() == pref.GroupKind {
-			name = string(fd.Message().Name())
+		if more {
+			e.s.WriteMore()
 		}
+		more = true
+		e.s.WriteObjectField(e.fieldName(fd))
 
+		var err error
 		switch {
 		case fd.IsList():
-			m[name] = itrList(val.List(), fd)
+			err = e.list(val.List(), fd)
 		case fd.IsMap():
-			m[name] = itrMap(val.Map(), fd)
+			err = e.mapValue(val.Map(), fd)
 		default:
-			m[name] = itrSingular(val, fd)
+			err = e.singular(val, fd)
+		}
+		if err != nil {
+			return err
 		}
-
 	}
+	return nil
+}
 
-	return m
+func (e *pbEncoder) fieldName(fd pref.FieldDescriptor) string {
+	if e.c.opts.naming == JSONNames {
+		return fd.JSONName()
+	}
+	// Use type name for group field name.
+	if fd.Kind() == pref.GroupKind {
+		return string(fd.Message().Name())
+	}
+	return string(fd.Name())
 }
 
-func itrList(list pref.List, fd pref.FieldDescriptor) interface{} {
-	l := make([]interface{}, 0)
+func (e *pbEncoder) list(list pref.List, fd pref.FieldDescriptor) error {
+	e.s.WriteArrayStart()
 	for i := 0; i < list.Len(); i++ {
-		item := list.Get(i)
-		l = append(l, itrSingular(item, fd))
+		if i > 0 {
+			e.s.WriteMore()
+		}
+		if err := e.singular(list.Get(i), fd); err != nil {
+			return err
+		}
 	}
-	return l
+	e.s.WriteArrayEnd()
+	return nil
 }
 
 // mapEntry kv实体
@@ -78,62 +144,167 @@ type mapEntry struct {
 	value pref.Value
 }
 
-func itrMap(mmap pref.Map, fd pref.FieldDescriptor) interface{} {
-	m := make(map[string]interface{})
-
-	// Get a sorted list based on keyType first.
+func (e *pbEncoder) mapValue(mmap pref.Map, fd pref.FieldDescriptor) error {
 	entries := make([]mapEntry, 0, mmap.Len())
 	mmap.Range(func(key pref.MapKey, val pref.Value) bool {
 		entries = append(entries, mapEntry{key: key, value: val})
 		return true
 	})
+	// 和protojson一致：bool按false、true，整数按数值，string按字典序
+	sort.Slice(entries, func(i, j int) bool {
+		switch x := entries[i].key.Interface().(type) {
+		case bool:
+			return !x && entries[j].key.Bool()
+		case int32, int64:
+			return entries[i].key.Int() < entries[j].key.Int()
+		case uint32, uint64:
+			return entries[i].key.Uint() < entries[j].key.Uint()
+		}
+		return entries[i].key.String() < entries[j].key.String()
+	})
 
-	// Write out sorted list.
-	for _, entry := range entries {
-		m[entry.key.String()] = itrSingular(entry.value, fd.MapValue())
+	e.s.WriteObjectStart()
+	for i, entry := range entries {
+		if i > 0 {
+			e.s.WriteMore()
+		}
+		e.s.WriteObjectField(entry.key.String())
+		if err := e.singular(entry.value, fd.MapValue()); err != nil {
+			return err
+		}
 	}
-	return m
+	e.s.WriteObjectEnd()
+	return nil
 }
 
-func itrSingular(val pref.Value, fd pref.FieldDescriptor) interface{} {
-
+func (e *pbEncoder) singular(val pref.Value, fd pref.FieldDescriptor) error {
 	if !val.IsValid() {
-		m := make(map[string]interface{})
-		return m
+		e.s.WriteNil()
+		return nil
 	}
 
-	var v interface{}
 	switch kind := fd.Kind(); kind {
 	case pref.BoolKind:
-		v = val.Bool()
+		e.s.WriteBool(val.Bool())
 
 	case pref.StringKind:
-		v = val.String()
+		e.s.WriteString(val.String())
 
 	case pref.Int32Kind, pref.Sint32Kind, pref.Sfixed32Kind,
 		pref.Int64Kind, pref.Sint64Kind, pref.Sfixed64Kind:
-		v = val.Int()
+		e.s.WriteInt64(val.Int())
 
 	case pref.Uint32Kind, pref.Fixed32Kind,
 		pref.Uint64Kind, pref.Fixed64Kind:
-		v = val.Uint()
+		e.s.WriteUint64(val.Uint())
 
 	case pref.FloatKind:
-		v = val.Float()
+		e.s.WriteRaw(formatFloat(val.Float(), 32))
 
 	case pref.DoubleKind:
-		v = val.Float()
+		e.s.WriteRaw(formatFloat(val.Float(), 64))
 
 	case pref.BytesKind:
-		v = base64.StdEncoding.EncodeToString(val.Bytes())
+		e.s.WriteString(base64.StdEncoding.EncodeToString(val.Bytes()))
 
 	case pref.EnumKind:
-		v = int64(val.Enum())
+		if fd.Enum().FullName() == "google.protobuf.NullValue" {
+			e.s.WriteNil()
+			return nil
+		}
+		if e.c.opts.enumStyle == EnumNames {
+			if ev := fd.Enum().Values().ByNumber(val.Enum()); ev != nil {
+				e.s.WriteString(string(ev.Name()))
+				return nil
+			}
+		}
+		e.s.WriteInt64(int64(val.Enum()))
 
 	case pref.MessageKind, pref.GroupKind:
-		v = itrMessage(val.Message())
+		return e.message(val.Message())
+
 	default:
+		return fmt.Errorf("xjson: %v has unknown kind %v", fd.FullName(), kind)
 	}
+	return nil
+}
+
+// wellKnown Timestamp、Duration、FieldMask、Struct、Value、ListValue、Empty的json形式中没有64位整数，交给protojson
+func (e *pbEncoder) wellKnown(m pref.Message) error {
+	opts := protojson.MarshalOptions{Resolver: e.c.opts.resolver}
+	data, err := opts.Marshal(m.Interface())
+	if err != nil {
+		return err
+	}
+	// protojson会随机插入空格，统一压缩为紧凑格式
+	var buf bytes.Buffer
+	if err := stdjson.Compact(&buf, data); err != nil {
+		return err
+	}
+	e.s.Write(buf.Bytes())
+	return nil
+}
 
-	return v
+// any 通过Resolver查找Any中的类型，输出"@type"和解码后的message，
+// message为well-known types时其json形式放在"value"中
+func (e *pbEncoder) any(m pref.Message) error {
+	md := m.Descriptor()
+	url := m.Get(md.Fields().ByName("type_url")).String()
+	value := m.Get(md.Fields().ByName("value")).Bytes()
+	if len(url) == 0 && len(value) == 0 {
+		e.s.WriteEmptyObject()
+		return nil
+	}
+	mt, err := e.c.opts.resolver.FindMessageByURL(url)
+	if err != nil {
+		return fmt.Errorf("xjson: unable to resolve %q: %v", url, err)
+	}
+	em := mt.New()
+	opts := proto.UnmarshalOptions{AllowPartial: true, Resolver: e.c.opts.resolver}
+	if err := opts.Unmarshal(value, em.Interface()); err != nil {
+		return fmt.Errorf("xjson: unable to unmarshal %q: %v", url, err)
+	}
+
+	e.s.WriteObjectStart()
+	e.s.WriteObjectField("@type")
+	e.s.WriteString(url)
+	name := em.Descriptor().FullName()
+	if name == "google.protobuf.Any" || wrapperTypes[name] || wellKnownTypes[name] {
+		e.s.WriteMore()
+		e.s.WriteObjectField("value")
+		if err := e.message(em); err != nil {
+			return err
+		}
+	} else if err := e.fields(em, true); err != nil {
+		return err
+	}
+	e.s.WriteObjectEnd()
+	return nil
+}
+
+// formatFloat 和protojson相同的浮点数格式，NaN和Infinity为string
+func formatFloat(n float64, bitSize int) string {
+	switch {
+	case math.IsNaN(n):
+		return `"NaN"`
+	case math.IsInf(n, +1):
+		return `"Infinity"`
+	case math.IsInf(n, -1):
+		return `"-Infinity"`
+	}
+	format := byte('f')
+	if abs := math.Abs(n); abs != 0 {
+		if bitSize == 64 && (abs < 1e-6 || abs >= 1e21) ||
+			bitSize == 32 && (float32(abs) < 1e-6 || float32(abs) >= 1e21) {
+			format = 'e'
+		}
+	}
+	s := strconv.FormatFloat(n, format, -1, bitSize)
+	// 和encoding/json一致，"1e-07"简化为"1e-7"
+	if format == 'e' {
+		if i := strings.Index(s, "e-0"); i >= 0 && len(s) == i+4 {
+			s = s[:i+2] + s[i+3:]
+		}
+	}
+	return s
 }
@@ -0,0 +1,130 @@
+package xjson
+
+import (
+	stdjson "encoding/json"
+	"fmt"
+	"io"
+)
+
+// NDJSONContentType newline-delimited json的content type
+const NDJSONContentType = "application/x-ndjson"
+
+// Encoder 把json值依次写入io.Writer，每个值后跟一个换行
+type Encoder struct {
+	c      *Codec
+	w      io.Writer
+	ndjson bool
+}
+
+// NewEncoder returns an Encoder of the default codec writing to w.
+func NewEncoder(w io.Writer) *Encoder {
+	return defaultCodec.NewEncoder(w)
+}
+
+// NewNDJSONEncoder returns an NDJSON Encoder of the default codec writing to w.
+func NewNDJSONEncoder(w io.Writer) *Encoder {
+	return defaultCodec.NewNDJSONEncoder(w)
+}
+
+// NewEncoder returns an Encoder writing to w, the values are marshaled by c.
+func (c *Codec) NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{c: c, w: w}
+}
+
+// NewNDJSONEncoder returns an Encoder writing newline-delimited json to w, one value per line.
+// The indent of c is ignored, and w is flushed after each line if it has a Flush method,
+// e.g. http.ResponseWriter, so the client of an export endpoint receives the lines as they are written.
+func (c *Codec) NewNDJSONEncoder(w io.Writer) *Encoder {
+	if len(c.opts.indent) > 0 {
+		cc := *c
+		cc.opts.indent = ""
+		c = &cc
+	}
+	return &Encoder{c: c, w: w, ndjson: true}
+}
+
+// Encode writes the json of v followed by a newline, v may be a proto.Message or a plain Go value.
+func (e *Encoder) Encode(v interface{}) error {
+	data, err := e.c.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if _, err := e.w.Write(append(data, '\n')); err != nil {
+		return err
+	}
+	if f, ok := e.w.(interface{ Flush() }); ok && e.ndjson {
+		f.Flush()
+	}
+	return nil
+}
+
+// Decoder 从io.Reader中依次读取json值，值之间可以是任意空白，因此同样适用于NDJSON
+type Decoder struct {
+	c *Codec
+	d *stdjson.Decoder
+}
+
+// NewDecoder returns a Decoder of the default codec reading from r.
+func NewDecoder(r io.Reader) *Decoder {
+	return defaultCodec.NewDecoder(r)
+}
+
+// NewDecoder returns a Decoder reading from r, the values are unmarshaled by c.
+func (c *Codec) NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{c: c, d: stdjson.NewDecoder(r)}
+}
+
+// Decode reads the next json value from the input and stores it in v, v may be a proto.Message
+// or a plain Go value, the proto fallback of Unmarshal applies. It returns io.EOF at the end of the input,
+// malformed or truncated values are returned as a *DecodeError like Unmarshal does.
+// Only the current value is buffered, not the whole input.
+func (d *Decoder) Decode(v interface{}) error {
+	var raw stdjson.RawMessage
+	if err := d.d.Decode(&raw); err != nil {
+		return d.syntaxError(err)
+	}
+	return d.c.Unmarshal(raw, v)
+}
+
+// syntaxError json语法错误和截断转为*DecodeError，offset为相对整个输入的位置，io.EOF等其他错误原样返回
+func (d *Decoder) syntaxError(err error) error {
+	fe := &FieldError{Expected: "json", Message: err.Error()}
+	switch e := err.(type) {
+	case *stdjson.SyntaxError:
+		fe.Offset = syntaxOffset(e)
+	default:
+		if err != io.ErrUnexpectedEOF {
+			return err
+		}
+		// 截断时未读完的值仍在Decoder的缓冲中，offset为输入的末尾
+		buffered, _ := io.Copy(io.Discard, d.d.Buffered())
+		fe.Offset = d.d.InputOffset() + buffered
+	}
+	return &DecodeError{Errors: []*FieldError{fe}}
+}
+
+// Finish returns a *DecodeError if anything but whitespace is left in the input.
+// Call it after the last Decode when the input must hold exactly the decoded values, e.g. a request body.
+func (d *Decoder) Finish() error {
+	start := d.d.InputOffset()
+	var raw stdjson.RawMessage
+	err := d.d.Decode(&raw)
+	if err == io.EOF {
+		return nil
+	}
+	fe := &FieldError{Expected: "end of input", Offset: start}
+	if se, ok := err.(*stdjson.SyntaxError); ok {
+		fe.Offset = syntaxOffset(se)
+	}
+	if err == nil {
+		fe.Value = snippet(raw)
+		err = fmt.Errorf("expected end of input, got %s", fe.Value)
+	}
+	fe.Message = err.Error()
+	return &DecodeError{Errors: []*FieldError{fe}}
+}
+
+// More reports whether there is another value in the input.
+func (d *Decoder) More() bool {
+	return d.d.More()
+}
@@ -0,0 +1,94 @@
+package test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/codermuhao/tools/xjson"
+)
+
+func TestCodec_Marshal(t *testing.T) {
+	outer := &Outer{OuterString: "s", Status: Status_Status_Success}
+	tests := []struct {
+		codec  *xjson.Codec
+		input  interface{}
+		expect string
+	}{
+		{
+			codec:  xjson.NewCodec(),
+			input:  outer,
+			expect: `{"outer_string":"s","inner":null,"status":1}`,
+		},
+		{
+			codec:  xjson.NewCodec(xjson.WithNaming(xjson.JSONNames), xjson.WithEnumStyle(xjson.EnumNames)),
+			input:  outer,
+			expect: `{"outerString":"s","inner":null,"status":"Status_Success"}`,
+		},
+		{
+			codec:  xjson.NewCodec(xjson.WithEmitUnpopulated(false)),
+			input:  &Outer{OuterString: "s"},
+			expect: `{"outer_string":"s"}`,
+		},
+		{
+			codec:  xjson.NewCodec(),
+			input:  &BigInt{BigintInt64: 1, BigintUint64: 2},
+			expect: `{"bigint_uint64":"2","bigint_int64":"1","bigint_sint64":"0","bigint_fixed64":"0","bigint_sfixed64":"0"}`,
+		},
+		{
+			codec:  xjson.NewCodec(xjson.WithInt64AsString(false), xjson.WithEmitUnpopulated(false)),
+			input:  &BigInt{BigintInt64: 1, BigintUint64: 2},
+			expect: `{"bigint_uint64":2,"bigint_int64":1}`,
+		},
+		{
+			codec: xjson.NewCodec(xjson.WithInt64AsString(false), xjson.WithNaming(xjson.JSONNames),
+				xjson.WithEnumStyle(xjson.EnumNames), xjson.WithEmitUnpopulated(false)),
+			input:  outer,
+			expect: `{"outerString":"s","status":"Status_Success"}`,
+		},
+		{
+			codec:  xjson.NewCodec(xjson.WithIndent("  ")),
+			input:  &Outer{Status: Status_Status_Failure},
+			expect: "{\n  \"outer_string\": \"\",\n  \"inner\": null,\n  \"status\": 2\n}",
+		},
+		{
+			codec:  xjson.NewCodec(xjson.WithIndent("\t")),
+			input:  map[string]int{"a": 1},
+			expect: "{\n\t\"a\": 1\n}",
+		},
+	}
+	for _, v := range tests {
+		data, err := v.codec.Marshal(v.input)
+		if err != nil {
+			t.Errorf("marshal(%v): %s", v.input, err)
+		}
+		got, want := string(data), v.expect
+		if !strings.Contains(want, "\n") {
+			got = compactJSON(data)
+		}
+		if got != want {
+			if strings.Contains(want, "\n") {
+				t.Errorf("marshal(%v):\nHAVE:\n%s\nWANT:\n%s", v.input, got, want)
+			} else {
+				t.Errorf("marshal(%v):\nhave %#q\nwant %#q", v.input, got, want)
+			}
+		}
+	}
+}
+
+func TestCodec_Unmarshal(t *testing.T) {
+	strict := xjson.NewCodec(xjson.WithDiscardUnknown(false))
+	if err := strict.Unmarshal([]byte(`{"a":"a","unknown":1}`), new(testMessage)); err == nil {
+		t.Errorf("unknown field should be rejected")
+	}
+	if err := xjson.NewCodec().Unmarshal([]byte(`{"a":"a","unknown":1}`), new(testMessage)); err != nil {
+		t.Errorf("unknown field should be discarded: %s", err)
+	}
+
+	m := new(Outer)
+	if err := xjson.NewCodec().Unmarshal([]byte(`{"outerString":"s","status":"Status_Failure"}`), m); err != nil {
+		t.Fatal(err)
+	}
+	if m.OuterString != "s" || m.Status != Status_Status_Failure {
+		t.Errorf("have %v", m)
+	}
+}
@@ -0,0 +1,134 @@
+package test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/codermuhao/tools/xjson"
+)
+
+func TestUnmarshal_DecodeError(t *testing.T) {
+	tests := []struct {
+		input  string
+		expect []xjson.FieldError
+	}{
+		{
+			input:  `{"int32_value":"abc"}`,
+			expect: []xjson.FieldError{{Path: "int32_value", Expected: "int32", Value: `"abc"`, Offset: 15}},
+		},
+		{
+			input:  `{"inner":{"inner_int":[1,2]}}`,
+			expect: []xjson.FieldError{{Path: "inner.inner_int", Expected: "int32", Value: `[1,2]`, Offset: 22}},
+		},
+		{
+			input: `{"status":"Unknown","inners":[{},{"inner_bool":"x"}]}`,
+			expect: []xjson.FieldError{
+				{Path: "status", Expected: "enum test.Status", Value: `"Unknown"`, Offset: 10},
+				{Path: "inners[1].inner_bool", Expected: "bool", Value: `"x"`, Offset: 47},
+			},
+		},
+		{
+			input: `{"wrapped_int64":"big","counts":{"a":null}}`,
+			expect: []xjson.FieldError{
+				{Path: "wrapped_int64", Expected: "int64", Value: `"big"`, Offset: 17},
+				{Path: "counts.a", Expected: "int32", Value: `null`, Offset: 37},
+			},
+		},
+		{
+			input:  `{"string_value":"` + strings.Repeat("中", 20) + `","int32_value":{}}`,
+			expect: []xjson.FieldError{{Path: "int32_value", Expected: "int32", Value: `{}`, Offset: 93}},
+		},
+		{
+			input: `{"int32_value":"x","inner":{`,
+			expect: []xjson.FieldError{
+				{Path: "int32_value", Expected: "int32", Value: `"x"`, Offset: 15},
+				{Path: "inner", Expected: "json", Offset: 28},
+			},
+		},
+	}
+	for _, v := range tests {
+		err := xjson.Unmarshal([]byte(v.input), new(Complex))
+		var de *xjson.DecodeError
+		if !errors.As(err, &de) {
+			t.Errorf("unmarshal(%s): %v is not a DecodeError", v.input, err)
+			continue
+		}
+		if len(de.Errors) != len(v.expect) {
+			t.Errorf("unmarshal(%s): have %d errors %v, want %d", v.input, len(de.Errors), err, len(v.expect))
+			continue
+		}
+		for i, fe := range de.Errors {
+			want := v.expect[i]
+			if fe.Path != want.Path || fe.Expected != want.Expected || fe.Value != want.Value || fe.Offset != want.Offset {
+				t.Errorf("unmarshal(%s): error %d have %+v, want %+v", v.input, i, *fe, want)
+			}
+			if len(fe.Message) == 0 || !strings.Contains(err.Error(), fe.Message) {
+				t.Errorf("unmarshal(%s): message %q missing from %q", v.input, fe.Message, err)
+			}
+		}
+	}
+}
+
+func TestUnmarshal_DecodeErrorSnippet(t *testing.T) {
+	input := `{"int32_value":"` + strings.Repeat("中", 20) + `"}`
+	err := xjson.Unmarshal([]byte(input), new(Complex))
+	var de *xjson.DecodeError
+	if !errors.As(err, &de) || len(de.Errors) != 1 {
+		t.Fatalf("unexpected error %v", err)
+	}
+	value := de.Errors[0].Value
+	if !strings.HasSuffix(value, "...") || len(value) > 35 || !strings.HasPrefix(value, `"中`) {
+		t.Errorf("snippet %q should be truncated at a rune boundary", value)
+	}
+}
+
+func TestUnmarshal_DecodeErrorSyntax(t *testing.T) {
+	tests := []struct {
+		input  string
+		path   string
+		offset int64
+	}{
+		{input: `{"outer_string": tru}`, path: "outer_string", offset: 20},
+		{input: `{"outer_string":"a" "status":1}`, path: "", offset: 20},
+		{input: `{"inner":{"inner_int":1,}}`, path: "inner", offset: 23},
+	}
+	for _, v := range tests {
+		err := xjson.Unmarshal([]byte(v.input), new(Outer))
+		de, ok := err.(*xjson.DecodeError)
+		if !ok || len(de.Errors) != 1 {
+			t.Errorf("unmarshal(%s): should report one error, have %v", v.input, err)
+			continue
+		}
+		if fe := de.Errors[0]; fe.Path != v.path || fe.Expected != "json" || fe.Offset != v.offset {
+			t.Errorf("unmarshal(%s): have %+v", v.input, *fe)
+		}
+	}
+}
+
+func TestUnmarshal_DecodeErrorField(t *testing.T) {
+	tests := []struct {
+		input string
+		path  string
+		field string
+	}{
+		{input: `{"inners":[{},{"InnerBool":"x"}]}`, path: "inners[1].InnerBool", field: "inners.inner_bool"},
+		{input: `{"counts":{"attacker-key":"x"}}`, path: "counts.attacker-key", field: "counts"},
+		{input: `{"inner_map":{"5":{"inner_int":"x"}}}`, path: "inner_map.5.inner_int", field: "inner_map"},
+		{input: `{"inner_map":{"k":{}}}`, path: "inner_map.k", field: "inner_map"},
+		{input: `{"inner":{"zzz_random":1}}`, path: "inner.zzz_random", field: ""},
+		{input: `{"userID":"x"}`, path: "userID", field: "user_id"},
+	}
+	strict := xjson.NewCodec(xjson.WithDiscardUnknown(false))
+	for _, v := range tests {
+		err := strict.Unmarshal([]byte(v.input), new(Complex))
+		de, ok := err.(*xjson.DecodeError)
+		if !ok || len(de.Errors) != 1 {
+			t.Errorf("unmarshal(%s): should report one error, have %v", v.input, err)
+			continue
+		}
+		if fe := de.Errors[0]; fe.Path != v.path || fe.Field != v.field {
+			t.Errorf("unmarshal(%s): have path %q field %q, want %q %q", v.input, fe.Path, fe.Field, v.path, v.field)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/codermuhao/tools/xjson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestUnmarshal_Lenient(t *testing.T) {
+	extra, _ := structpb.NewStruct(map[string]interface{}{"k": "v"})
+	tests := []struct {
+		input  string
+		expect *Complex
+	}{
+		{
+			input:  `{"int32_value":"123","int64Value":"1e3","uint32_value":"7","double_value":"1.5","status":"2"}`,
+			expect: &Complex{Int32Value: 123, Int64Value: 1000, Uint32Value: 7, DoubleValue: 1.5, Status: Status_Status_Failure},
+		},
+		{
+			input:  `{"bool_value":"1","string_value":12,"bytes_value":"aGk","status":"Status_Success","inner":""}`,
+			expect: &Complex{BoolValue: true, StringValue: "12", BytesValue: []byte("hi"), Status: Status_Status_Success},
+		},
+		{
+			input: `{"Inner":{"InnerInt":"3"},"inners":[{"inner_string":"a"},{}],"int64_list":["1",2],"userID":"9",` +
+				`"status":"1"}`,
+			expect: &Complex{Inner: &Inner{InnerInt: 3}, Inners: []*Inner{{InnerString: "a"}, {}},
+				Int64List: []int64{1, 2}, UserId: 9, Status: Status_Status_Success},
+		},
+		{
+			input: `{"counts":{"a":"1"},"inner_map":{"5":{"inner_bool":"true"}},"choice_id":"4","optional_value":"0"}`,
+			expect: &Complex{Counts: map[string]int32{"a": 1}, InnerMap: map[int64]*Inner{5: {InnerBool: true}},
+				Choice: &Complex_ChoiceId{ChoiceId: 4}, OptionalValue: proto.Int32(0)},
+		},
+		{
+			input: `{"created_at":"2021-01-02T03:04:05Z","wrapped_int64":"42","extra":{"k":"v"},"ttl":"1.5s",` +
+				`"choice_name":null,"choice_id":"1","int32_value":"1"}`,
+			expect: &Complex{CreatedAt: timestamppb.New(time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)),
+				WrappedInt64: wrapperspb.Int64(42), Extra: extra, Ttl: durationpb.New(1500 * time.Millisecond),
+				Choice: &Complex_ChoiceId{ChoiceId: 1}, Int32Value: 1},
+		},
+		{
+			input:  `{"created_at":"","wrapped_int64":null,"unknown":{"a":[1]},"int32_value":"5"}`,
+			expect: &Complex{Int32Value: 5},
+		},
+	}
+	for _, v := range tests {
+		got := new(Complex)
+		if err := xjson.Unmarshal([]byte(v.input), got); err != nil {
+			t.Errorf("unmarshal(%s): %s", v.input, err)
+			continue
+		}
+		if !proto.Equal(got, v.expect) {
+			t.Errorf("unmarshal(%s):\nhave %v\nwant %v", v.input, got, v.expect)
+		}
+	}
+}
+
+func TestUnmarshal_LenientError(t *testing.T) {
+	tests := []string{
+		`{"int32_value":"abc"}`,
+		`{"int32_value":"1.5"}`,
+		`{"int32_value":"3000000000"}`,
+		`{"status":"Unknown"}`,
+		`{"choice_id":"1","choice_name":"x"}`,
+		`{"int64_list":[1,null]}`,
+		`{"inner":[1]}`,
+		`{"int32_value":true}`,
+		`{"int32_value":"1"} {}`,
+	}
+	for _, v := range tests {
+		if err := xjson.Unmarshal([]byte(v), new(Complex)); err == nil {
+			t.Errorf("unmarshal(%s) should fail", v)
+		}
+	}
+	strict := xjson.NewCodec(xjson.WithDiscardUnknown(false))
+	if err := strict.Unmarshal([]byte(`{"int32_value":"1","unknown":1}`), new(Complex)); err == nil {
+		t.Errorf("unknown field should be rejected")
+	}
+}
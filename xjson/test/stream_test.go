@@ -0,0 +1,147 @@
+package test
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/codermuhao/tools/xjson"
+)
+
+type flushBuffer struct {
+	bytes.Buffer
+	flushed int
+}
+
+func (f *flushBuffer) Flush() {
+	f.flushed++
+}
+
+func TestEncoder(t *testing.T) {
+	var buf bytes.Buffer
+	e := xjson.NewEncoder(&buf)
+	for _, v := range []interface{}{
+		&Outer{OuterString: "a", Status: Status_Status_Success},
+		&testMessage{Field1: "b"},
+	} {
+		if err := e.Encode(v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var got string
+	for _, line := range strings.SplitAfter(buf.String(), "\n") {
+		if strings.HasSuffix(line, "\n") {
+			got += compactJSON([]byte(line)) + "\n"
+		}
+	}
+	want := `{"outer_string":"a","inner":null,"status":1}` + "\n" + `{"a":"b","b":"","c":""}` + "\n"
+	if got != want || !strings.HasSuffix(buf.String(), "\n") {
+		t.Errorf("have %#q want %#q", buf.String(), want)
+	}
+
+	fb := new(flushBuffer)
+	e = xjson.NewCodec(xjson.WithIndent("  ")).NewNDJSONEncoder(fb)
+	for i := 0; i < 3; i++ {
+		if err := e.Encode(&Inner{InnerInt: int32(i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	lines := strings.Split(strings.TrimSuffix(fb.String(), "\n"), "\n")
+	if len(lines) != 3 || fb.flushed != 3 {
+		t.Errorf("have %d lines %d flushes: %s", len(lines), fb.flushed, fb.String())
+	}
+}
+
+func TestDecoder(t *testing.T) {
+	input := `{"outer_string":"a","status":1}
+{"outer_string":"b","status":"2"}
+
+{"outer_string":"c","status":0}`
+	d := xjson.NewDecoder(strings.NewReader(input))
+	var got []string
+	for d.More() {
+		m := new(Outer)
+		if err := d.Decode(m); err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, m.OuterString)
+		if m.OuterString == "b" && m.Status != Status_Status_Failure {
+			t.Errorf("fallback is not applied: %v", m)
+		}
+	}
+	if strings.Join(got, ",") != "a,b,c" {
+		t.Errorf("have %v", got)
+	}
+	if err := d.Decode(new(Outer)); err != io.EOF {
+		t.Errorf("have %v want EOF", err)
+	}
+
+	d = xjson.NewDecoder(strings.NewReader(`{"a":"x"} [1,2]`))
+	m, list := new(testMessage), []int(nil)
+	if err := d.Decode(m); err != nil || m.Field1 != "x" {
+		t.Errorf("have %v %v", m, err)
+	}
+	if err := d.Decode(&list); err != nil || len(list) != 2 {
+		t.Errorf("have %v %v", list, err)
+	}
+	if err := xjson.NewDecoder(strings.NewReader(`{"a":`)).Decode(new(testMessage)); err == nil {
+		t.Errorf("truncated input should fail")
+	}
+}
+
+func TestDecoder_SyntaxError(t *testing.T) {
+	tests := []struct {
+		input  string
+		offset int64
+	}{
+		{input: `{"outer_string":}`, offset: 16},
+		{input: `{"outer_string":`, offset: 16},
+		{input: `{"outer_string":"a"} {"outer_string":"b",`, offset: 41},
+		{input: `{"outer_string":"a"} [1,]`, offset: 24},
+	}
+	for _, v := range tests {
+		d := xjson.NewDecoder(strings.NewReader(v.input))
+		var err error
+		for err == nil {
+			err = d.Decode(new(Outer))
+		}
+		de, ok := err.(*xjson.DecodeError)
+		if !ok || len(de.Errors) != 1 || de.Errors[0].Offset != v.offset || de.Errors[0].Expected != "json" {
+			t.Errorf("decode(%s): have %#v", v.input, err)
+		}
+	}
+}
+
+func TestDecoder_Finish(t *testing.T) {
+	tests := []struct {
+		input  string
+		offset int64
+		fail   bool
+	}{
+		{input: `{"outer_string":"a"}`},
+		{input: "{\"outer_string\":\"a\"} \n\t"},
+		{input: ``},
+		{input: `{"outer_string":"a"} garbage`, offset: 21, fail: true},
+		{input: `{"outer_string":"a"}}`, offset: 20, fail: true},
+		{input: `{"outer_string":"a"} {"outer_string":"b"}`, offset: 20, fail: true},
+	}
+	for _, v := range tests {
+		d := xjson.NewDecoder(strings.NewReader(v.input))
+		if err := d.Decode(new(Outer)); err != nil && err != io.EOF {
+			t.Errorf("decode(%s): %v", v.input, err)
+			continue
+		}
+		err := d.Finish()
+		if !v.fail {
+			if err != nil {
+				t.Errorf("finish(%s): %v", v.input, err)
+			}
+			continue
+		}
+		de, ok := err.(*xjson.DecodeError)
+		if !ok || len(de.Errors) != 1 || de.Errors[0].Offset != v.offset || de.Errors[0].Expected != "end of input" {
+			t.Errorf("finish(%s): have %v", v.input, err)
+		}
+	}
+}
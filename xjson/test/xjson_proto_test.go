@@ -2,6 +2,7 @@ package test
 
 import (
 	"bytes"
+	stdjson "encoding/json"
 	"strings"
 	"testing"
 
@@ -36,7 +37,7 @@ func TestProto_JSON_Marshal(t *testing.T) {
 		if err != nil {
 			t.Errorf("marshal(%#v): %s", v.input, err)
 		}
-		if got, want := string(data), v.expect; got != want {
+		if got, want := compactJSON(data), compactJSON([]byte(v.expect)); got != want {
 			if strings.Contains(want, "\n") {
 				t.Errorf("marshal(%#v):\nHAVE:\n%s\nWANT:\n%s", v.input, got, want)
 			} else {
@@ -77,8 +78,17 @@ func TestProto_JSON_Unmarshal(t *testing.T) {
 		if err != nil {
 			t.Errorf("marshal(%#v): %s", v.input, err)
 		}
-		if !bytes.Equal(got, want) {
+		if compactJSON(got) != compactJSON(want) {
 			t.Errorf("marshal(%#v):\nhave %#q\nwant %#q", v.input, got, want)
 		}
 	}
 }
+
+// compactJSON protojson按二进制的hash随机在逗号后插入空格，比较前统一压缩
+func compactJSON(data []byte) string {
+	var buf bytes.Buffer
+	if err := stdjson.Compact(&buf, data); err != nil {
+		return string(data)
+	}
+	return buf.String()
+}
@@ -1,11 +1,20 @@
 package test
 
 import (
+	"bytes"
+	stdjson "encoding/json"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/codermuhao/tools/xjson"
 	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
 )
 
 func TestXMarshalPB(t *testing.T) {
@@ -15,11 +24,11 @@ func TestXMarshalPB(t *testing.T) {
 	}{
 		{
 			input:  &BigInt{},
-			expect: `{"bigint_fixed64":0,"bigint_int64":0,"bigint_sfixed64":0,"bigint_sint64":0,"bigint_uint64":0}`,
+			expect: `{"bigint_uint64":0,"bigint_int64":0,"bigint_sint64":0,"bigint_fixed64":0,"bigint_sfixed64":0}`,
 		},
 		{
 			input:  &BigInt{BigintInt64: 1, BigintFixed64: 2, BigintSfixed64: 3, BigintSint64: 4, BigintUint64: 5},
-			expect: `{"bigint_fixed64":2,"bigint_int64":1,"bigint_sfixed64":3,"bigint_sint64":4,"bigint_uint64":5}`,
+			expect: `{"bigint_uint64":5,"bigint_int64":1,"bigint_sint64":4,"bigint_fixed64":2,"bigint_sfixed64":3}`,
 		},
 	}
 	for _, v := range tests {
@@ -36,3 +45,121 @@ func TestXMarshalPB(t *testing.T) {
 		}
 	}
 }
+
+func newComplex(t *testing.T) *Complex {
+	extra, _ := structpb.NewStruct(map[string]interface{}{"k": "v"})
+	detail, err := anypb.New(&Inner{InnerInt: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &Complex{
+		Int64Value:    1<<53 + 1,
+		DoubleValue:   1e-7,
+		InnerMap:      map[int64]*Inner{10: {InnerInt: 1}, 2: {}, -1: {}},
+		Choice:        &Complex_ChoiceName{ChoiceName: "x"},
+		OptionalValue: proto.Int32(0),
+		CreatedAt:     timestamppb.New(time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)),
+		WrappedInt64:  wrapperspb.Int64(1<<53 + 1),
+		Extra:         extra,
+		Ttl:           durationpb.New(1500 * time.Millisecond),
+		Detail:        detail,
+	}
+}
+
+func TestXMarshalPB_WellKnown(t *testing.T) {
+	timestamp, _ := anypb.New(timestamppb.New(time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)))
+	tests := []struct {
+		codec  *xjson.Codec
+		input  proto.Message
+		expect string
+	}{
+		{
+			codec: xjson.NewCodec(xjson.WithInt64AsString(false)),
+			input: &Complex{},
+			expect: `{"int32_value":0,"int64_value":0,"uint32_value":0,"double_value":0,"bool_value":false,` +
+				`"string_value":"","bytes_value":"","status":0,"inner":null,"inners":[],"int64_list":[],"counts":{},` +
+				`"inner_map":{},"created_at":null,"wrapped_int64":null,"extra":null,"ttl":null,"detail":null,"user_id":0}`,
+		},
+		{
+			codec: xjson.NewCodec(xjson.WithInt64AsString(false), xjson.WithEmitUnpopulated(false)),
+			input: newComplex(t),
+			expect: `{"int64_value":9007199254740993,"double_value":1e-7,` +
+				`"inner_map":{"-1":{},"2":{},"10":{"inner_int":1}},"choice_name":"x","optional_value":0,` +
+				`"created_at":"2021-01-02T03:04:05Z","wrapped_int64":9007199254740993,"extra":{"k":"v"},"ttl":"1.500s",` +
+				`"detail":{"@type":"type.googleapis.com/test.Inner","inner_int":3}}`,
+		},
+		{
+			codec: xjson.NewCodec(xjson.WithInt64AsString(false), xjson.WithEmitUnpopulated(false),
+				xjson.WithNaming(xjson.JSONNames), xjson.WithEnumStyle(xjson.EnumNames)),
+			input:  &Complex{Status: Status_Status_Failure, Detail: timestamp, UserId: -1},
+			expect: `{"status":"Status_Failure","detail":{"@type":"type.googleapis.com/google.protobuf.Timestamp","value":"2021-01-02T03:04:05Z"},"userId":-1}`,
+		},
+	}
+	for _, v := range tests {
+		data, err := v.codec.Marshal(v.input)
+		if err != nil {
+			t.Errorf("marshal(%v): %s", v.input, err)
+			continue
+		}
+		if got, want := string(data), v.expect; got != want {
+			t.Errorf("marshal(%v):\nhave %#q\nwant %#q", v.input, got, want)
+		}
+	}
+}
+
+// 不含64位整数时XMarshalPB和protojson的输出除空白外一致
+func TestXMarshalPB_Protojson(t *testing.T) {
+	timestamp, _ := anypb.New(timestamppb.New(time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)))
+	extra, _ := structpb.NewStruct(map[string]interface{}{"b": []interface{}{1.5, nil, true}, "a": "<&>"})
+	tests := []proto.Message{
+		&Outer{Inner: &Inner{}},
+		&Outer{OuterString: "s", Inner: &Inner{InnerRepeatedFloat: []float32{1.1, 2.2}}, Status: Status_Status_Success},
+		&Complex{Counts: map[string]int32{"b": 2, "a": 1}, Detail: timestamp, Extra: extra, Ttl: durationpb.New(-time.Second)},
+	}
+	xpb := xjson.NewCodec(xjson.WithInt64AsString(false), xjson.WithEmitUnpopulated(false))
+	pj := xjson.NewCodec(xjson.WithEmitUnpopulated(false))
+	for _, v := range tests {
+		got, err := xpb.Marshal(v)
+		if err != nil {
+			t.Errorf("marshal(%v): %s", v, err)
+			continue
+		}
+		data, _ := pj.Marshal(v)
+		var want bytes.Buffer
+		_ = stdjson.Compact(&want, data)
+		if string(got) != want.String() {
+			t.Errorf("marshal(%v):\nhave %s\nwant %s", v, got, want.String())
+		}
+	}
+}
+
+func TestXUnmarshalPB(t *testing.T) {
+	input := newComplex(t)
+	data, err := xjson.XMarshalPB(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := new(Complex)
+	if err := xjson.XUnmarshalPB(data, got); err != nil {
+		t.Fatalf("unmarshal(%s): %s", data, err)
+	}
+	if !proto.Equal(got, input) {
+		t.Errorf("unmarshal(%s):\nhave %v\nwant %v", data, got, input)
+	}
+	if err := xjson.XUnmarshalPB([]byte(`{"int64_value":"12","user_id":13}`), got); err != nil || got.Int64Value != 12 || got.UserId != 13 {
+		t.Errorf("unmarshal: have %v, %v", got, err)
+	}
+}
+
+func TestXMarshalPB_Resolver(t *testing.T) {
+	detail, _ := anypb.New(&Inner{InnerInt: 3})
+	input := &Complex{Detail: detail}
+	codec := xjson.NewCodec(xjson.WithInt64AsString(false), xjson.WithResolver(new(protoregistry.Types)))
+	if _, err := codec.Marshal(input); err == nil {
+		t.Errorf("Any should not be resolved by an empty registry")
+	}
+	input.Detail = &anypb.Any{TypeUrl: "type.googleapis.com/test.Unknown"}
+	if _, err := xjson.XMarshalPB(input); err == nil {
+		t.Errorf("Any of an unknown type should fail")
+	}
+}